@@ -19,6 +19,7 @@ package v1
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -31,7 +32,7 @@ import (
  */
 
 // Category defines the type of the GreatSql
-// Supported values are "SingleInstance" "GroupReplicationCluster"
+// Supported values are "SingleInstance" "ReplicaofGroupCluster" "GroupReplicationCluster"
 // SingleInstance: SingleInstance instance of GreatSql
 // ReplicaofCluster: Master-slave replication cluster
 // TODO: GroupReplicationCluster: GroupReplicationCluster of a GreatSql cluster(MGR)
@@ -58,10 +59,44 @@ type MySQLGroupReplicationCluster struct {
 	DnsPolicy      corev1.DNSPolicy               `json:"dnsPolicy,omitempty"`
 	UpgradeOptions UpgradeOptions                 `json:"upgradeOptions,omitempty"`
 	UpdateStrategy *StatefulSetUpdateStrategyType `json:"updateStrategy,omitempty"`
+	ReplicaLag     *ReplicaLagPolicy              `json:"replicaLag,omitempty"`
+	// Election sets the weights of the members in the election of a new primary
+	Election *ElectionSpec `json:"election,omitempty"`
 	// Partition      *int32                         `json:"partition,omitempty"`
 	// MaxUnavailable *intstr.IntOrString            `json:"maxUnavailable,omitempty"`
 }
 
+// ReplicaLagPolicy defines when a lagging secondary is removed from the read-only service
+type ReplicaLagPolicy struct {
+	// MaxTransactionsInQueue is the applier queue size above which a secondary stops serving reads
+	//+kubebuilder:validation:Minimum=0
+	MaxTransactionsInQueue *int64 `json:"maxTransactionsInQueue,omitempty"`
+	// MaxSecondsBehind is the applier delay in seconds above which a secondary stops serving reads
+	//+kubebuilder:validation:Minimum=0
+	MaxSecondsBehind *int64 `json:"maxSecondsBehind,omitempty"`
+	// RecoveryPercent is the percentage of the thresholds the lag has to fall below
+	// before an excluded secondary serves reads again
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=100
+	//+kubebuilder:default=50
+	RecoveryPercent *int32 `json:"recoveryPercent,omitempty"`
+}
+
+// ElectionSpec defines the group_replication_member_weight of the members, the ONLINE secondary with the
+// highest weight is elected when the primary leaves the group. Arbitrators always have the weight 0
+type ElectionSpec struct {
+	// DefaultWeight is the weight of the members without a member or zone weight
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=100
+	//+kubebuilder:default=50
+	DefaultWeight *int32 `json:"defaultWeight,omitempty"`
+	// ZoneWeights are the weights of the members running on a node of the zone (topology.kubernetes.io/zone),
+	// for example a higher weight in the zone of the application
+	ZoneWeights map[string]int32 `json:"zoneWeights,omitempty"`
+	// MemberWeights are the weights of the members by pod name, they take precedence over the zone weights
+	MemberWeights map[string]int32 `json:"memberWeights,omitempty"`
+}
+
 type StatefulSetUpdateStrategyType struct {
 	Type           appsv1.StatefulSetUpdateStrategyType `json:"type,omitempty"`
 	RolelingUpdate *RolelingUpdate                      `json:"rolelingUpdate,omitempty"`
@@ -107,26 +142,202 @@ type PodSpec struct {
 	ServiceAccountName            string                     `json:"serviceAccountName,omitempty"`
 	ServiceName                   string                     `json:"serviceName,omitempty"`
 	Version                       string                     `json:"version,omitempty"`
+	// Containers defines the greatsql container, only the first one is used, see Sidecars for additional containers
 	//+Optional
 	Containers                    []ContainerSpec                   `json:"containers,omitempty"` // container spec
 	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"persistentVolumeClaimTemplate,omitempty"`
-	// Storage    *Storage        `json:"storage,omitempty"`
+	// BuiltinProbes replaces the probe handlers of the greatsql container with the built-in health check,
+	// the timing fields of the user probes are kept. Set to false to use the user probes as they are.
+	//+kubebuilder:default=true
+	//+optional
+	BuiltinProbes *bool `json:"builtinProbes,omitempty"`
+	// Storage separates the binlog, redo, undo and tmp files from the data directory
+	Storage *Storage `json:"storage,omitempty"`
+	// ImagePullSecrets of the pod, merged with the image pull secrets of the containers
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// InitContainers run in order after the init containers of the operator, once the config is rendered
+	// and the data dir is initialized, before greatsql starts
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// Sidecars run in order beside the greatsql container and the agent, for example a log shipper
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+	// ExtraVolumes are added to the volumes of the pod after the volumes of the operator
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+	// ExtraVolumeMounts are added to the volume mounts of the greatsql container, of the data dir init container and of the agent
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+	// PriorityClassName of the pod
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// RuntimeClassName of the pod
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
 }
 
-// TODO: not implemented yet
-// PersistentVolumeClaimTemplate 创建pvc后会自动关联创建pv，
-// PersistentVolumeSource 是用于定义PV的持久卷的资源，暂时不考虑支持创建PV，只支持PVC
+// Storage defines the volume layout of the instance, each volume of the layout has its own PersistentVolumeClaim,
+// the files without a volume are kept in the data volume. The layout is fixed once the instance is created
+// +kubebuilder:validation:XValidation:rule="!has(self.autoGrow) || !has(self.data) || !has(self.data.size) || !quantity(string(self.autoGrow.maxSize)).isLessThan(quantity(string(self.data.size)))",message="autoGrow.maxSize must not be smaller than the size of the data volume"
 type Storage struct {
-	Type                          string                            `json:"type,omitempty"`
-	PersistentVolumeSource        *corev1.PersistentVolumeSource    `json:"persistentVolumeSource,omitempty"`
-	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"persistentVolumeClaimTemplate,omitempty"`
+	// Data is the volume of the data directory, the persistentVolumeClaimTemplate of the pod is used if empty
+	Data *VolumeSpec `json:"data,omitempty"`
+	// Binlog is the volume of the binary logs (log_bin)
+	Binlog *VolumeSpec `json:"binlog,omitempty"`
+	// Redo is the volume of the redo log (innodb_log_group_home_dir)
+	Redo *VolumeSpec `json:"redo,omitempty"`
+	// Undo is the volume of the undo tablespaces (innodb_undo_directory)
+	Undo *VolumeSpec `json:"undo,omitempty"`
+	// Tmp is the volume of the temporary files (tmpdir)
+	Tmp *VolumeSpec `json:"tmp,omitempty"`
+	// AutoGrow grows the PersistentVolumeClaims before the volumes fill up, the storage classes must allow expansion
+	AutoGrow *StorageAutoGrow `json:"autoGrow,omitempty"`
+}
+
+// StorageAutoGrow defines how the PersistentVolumeClaims of the members grow with the usage of their volumes
+// +kubebuilder:validation:XValidation:rule="quantity(string(self.step)).isGreaterThan(quantity('0'))",message="step must be greater than 0"
+type StorageAutoGrow struct {
+	// ThresholdPercent is the usage of a volume above which its claim is grown
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=99
+	//+kubebuilder:default=80
+	ThresholdPercent int32 `json:"thresholdPercent,omitempty"`
+	// Step is the size added to the claim each time it grows
+	Step resource.Quantity `json:"step"`
+	// MaxSize is the size a claim never grows beyond, it can not be smaller than the size of the data volume
+	MaxSize resource.Quantity `json:"maxSize"`
+}
+
+// VolumeSpec defines a volume of the storage layout
+type VolumeSpec struct {
+	// StorageClassName of the PersistentVolumeClaim, the default class if empty
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// Size of the PersistentVolumeClaim
+	Size resource.Quantity `json:"size,omitempty"`
+	// EmptyDir uses an emptyDir instead of a PersistentVolumeClaim, the files are lost with the pod
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+}
+
+// DeletionPolicy defines what happens to the data of the instance when it is deleted
+// +kubebuilder:validation:Enum=Retain;Delete;Snapshot
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the PersistentVolumeClaims, a new instance with the same name reuses them
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyDelete deletes the PersistentVolumeClaims
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicySnapshot takes a final VolumeSnapshot of each PersistentVolumeClaim, then deletes them,
+	// the deletion is blocked until the snapshots are ready to use
+	DeletionPolicySnapshot DeletionPolicy = "Snapshot"
+)
+
+// conditions of the instances
+const (
+	// ConditionPaused is true while the reconciliation of the instance is paused
+	ConditionPaused = "Paused"
+	// ConditionHibernated is true once the instance is scaled to zero pods
+	ConditionHibernated = "Hibernated"
+	// ConditionAvailable is true while the instance serves clients
+	ConditionAvailable = "Available"
+	// ConditionProgressing is true while the pods of the instance are rolled out
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded is true if the reconciliation fails or a pod is not ready
+	ConditionDegraded = "Degraded"
+)
+
+// PodDisruptionBudgetSpec defines the PodDisruptionBudget of the instance,
+// only one of MinAvailable and MaxUnavailable can be set. A budget which allows no disruption,
+// e.g. minAvailable 1 or maxUnavailable 0 for a single instance, blocks the drain of its node
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="only one of minAvailable and maxUnavailable can be set"
+type PodDisruptionBudgetSpec struct {
+	MinAvailable   *intstr.IntOrString `json:"minAvailable,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
-// PodAffinity defines the affinity/anti-affinity rules for the pod.
+// InitSQLSource references a ConfigMap or a Secret holding sql files, all of its keys are applied sorted by name.
+// Only one of ConfigMapRef and SecretRef can be set
+type InitSQLSource struct {
+	ConfigMapRef *corev1.LocalObjectReference `json:"configMapRef,omitempty"`
+	SecretRef    *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// InitSQLPhase is the phase of the init sql scripts
+type InitSQLPhase string
+
+const (
+	// InitSQLRunning is set before the scripts are started on the primary, a run interrupted once the primary
+	// accepted it is not applied again
+	InitSQLRunning InitSQLPhase = "Running"
+	// InitSQLCompleted is set once the scripts are applied
+	InitSQLCompleted InitSQLPhase = "Completed"
+	// InitSQLFailed is set if a script failed, the scripts are not applied again
+	InitSQLFailed InitSQLPhase = "Failed"
+	// InitSQLSkipped is set if the data of the instance is copied from another one, or if the instance
+	// became available without scripts so that scripts added later are not applied to its data
+	InitSQLSkipped InitSQLPhase = "Skipped"
+)
+
+// InitSQLStatus defines the observed state of the init sql scripts, they are applied once whatever the phase
+type InitSQLStatus struct {
+	Phase InitSQLPhase `json:"phase,omitempty"`
+	// Scripts are the applied scripts as <kind>/<name>/<key>
+	Scripts []string `json:"scripts,omitempty"`
+	// StartTime is the time the primary accepted the scripts, a Running phase without it starts them again
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the scripts were applied
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	Message        string       `json:"message,omitempty"`
+}
+
+// AntiAffinityMode defines how strictly the pods of the instance are kept in different topology domains
+// +kubebuilder:validation:Enum=Preferred;Required;Disabled
+type AntiAffinityMode string
+
+const (
+	// AntiAffinityPreferred lets the scheduler put pods together when no other topology domain fits
+	AntiAffinityPreferred AntiAffinityMode = "Preferred"
+	// AntiAffinityRequired leaves a pod pending until a topology domain without a pod of the instance fits
+	AntiAffinityRequired AntiAffinityMode = "Required"
+	// AntiAffinityDisabled does not keep the pods apart
+	AntiAffinityDisabled AntiAffinityMode = "Disabled"
+)
+
+// ZoneLabel is the well-known node label of the zone
+const ZoneLabel = "topology.kubernetes.io/zone"
+
+// PodAffinity defines the placement of the pods of the instance
 type PodAffinity struct {
-	//+builder:default="kubernetes.io/hostname"
-	//+Optional
+	// TopologyKey is the node label of the topology domains the pods of the instance are kept apart in
+	//+kubebuilder:default="kubernetes.io/hostname"
+	//+optional
 	TopologyKey *string `json:"antiAffinityTopologyKey,omitempty"`
+	// AntiAffinity keeps the pods of the instance in different topology domains
+	//+kubebuilder:default=Preferred
+	//+optional
+	AntiAffinity AntiAffinityMode `json:"antiAffinity,omitempty"`
+	// ZoneSpread spreads the pods of the instance evenly across the zones of the nodes
+	//+optional
+	ZoneSpread *ZoneSpread `json:"zoneSpread,omitempty"`
+	// TopologySpreadConstraints of the pods, an empty label selector selects the pods of the instance
+	//+optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// NodeAffinity of the pods of the instance
+	//+optional
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+	// PrimaryNodeSelector selects the nodes preferred for the primary of a GroupReplicationCluster, the primary
+	// is switched over to an ONLINE member running on one of them
+	//+optional
+	PrimaryNodeSelector *corev1.NodeSelector `json:"primaryNodeSelector,omitempty"`
+	// Custom replaces the affinity built from the fields above, the topology spread constraints are kept
+	//+optional
+	Custom *corev1.Affinity `json:"custom,omitempty"`
+}
+
+// ZoneSpread defines how evenly the pods are spread across the zones
+type ZoneSpread struct {
+	// MaxSkew is the maximum difference of the number of pods between two zones
+	//+kubebuilder:default=1
+	//+kubebuilder:validation:Minimum=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+	// WhenUnsatisfiable is DoNotSchedule to leave a pod pending rather than skew the zones
+	//+kubebuilder:default=ScheduleAnyway
+	//+kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
 }
 
 // ContainerSpec defines the desired state of the container
@@ -165,66 +376,93 @@ type ServiceExpose struct {
 
 // PodAffinity returns the SingleInstance pod affinity of the resource
 func (s *SingleInstance) PodAffinity(labels map[string]string) *corev1.Affinity {
-	return SetPodAffinity(s.Spec, labels)
+	return s.Spec.PodSpec.Affinity.Affinity(labels)
 }
 
 // PodAffinity returns the SingleInstance primary group cluster pod affinity of the resource
 func (s *GroupReplicationCluster) PodAffinity(labels map[string]string) *corev1.Affinity {
-	return SetPodAffinity(s.Spec, labels)
+	if s.Spec.ClusterSpec.PodSpec == nil {
+		return nil
+	}
+	return s.Spec.ClusterSpec.PodSpec.Affinity.Affinity(labels)
 }
 
-// SetPodAffinity sets the pod affinity of the resource
-func SetPodAffinity(spec interface{}, labels map[string]string) *corev1.Affinity {
-	var topologyKey *string
+// PodAffinity returns the ReplicaofGroupCluster pod affinity of the resource
+func (s *ReplicaofGroupCluster) PodAffinity(labels map[string]string) *corev1.Affinity {
+	return s.Spec.PodSpec.Affinity.Affinity(labels)
+}
 
-	switch spec := spec.(type) {
-	case SingleInstance:
-		topologyKey = spec.Spec.PodSpec.Affinity.TopologyKey
-	case GroupReplicationCluster:
-		topologyKey = spec.Spec.ClusterSpec.PodSpec.Affinity.TopologyKey
+// Affinity returns the affinity of the pods selected by the labels, nil if no placement is set
+func (p *PodAffinity) Affinity(labels map[string]string) *corev1.Affinity {
+	if p == nil {
+		return nil
+	}
+	if p.Custom != nil {
+		return p.Custom.DeepCopy()
+	}
+
+	affinity := &corev1.Affinity{}
+	if p.NodeAffinity != nil {
+		affinity.NodeAffinity = p.NodeAffinity.DeepCopy()
+	}
+
+	topologyKey := "kubernetes.io/hostname"
+	if p.TopologyKey != nil && *p.TopologyKey != "" {
+		topologyKey = *p.TopologyKey
+	}
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: labels},
+		TopologyKey:   topologyKey,
+	}
+	switch p.AntiAffinity {
+	case AntiAffinityDisabled:
+	case AntiAffinityRequired:
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+		}
 	default:
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: term}},
+		}
+	}
+
+	if affinity.NodeAffinity == nil && affinity.PodAntiAffinity == nil {
 		return nil
 	}
+	return affinity
+}
 
-	if topologyKey == nil {
+// SpreadConstraints returns the topology spread constraints of the pods selected by the labels
+func (p *PodAffinity) SpreadConstraints(labels map[string]string) []corev1.TopologySpreadConstraint {
+	if p == nil {
 		return nil
 	}
 
-	return &corev1.Affinity{
-		PodAffinity: &corev1.PodAffinity{
-			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
-				{
-					LabelSelector: &metav1.LabelSelector{
-						MatchLabels: labels,
-					},
-					TopologyKey: *topologyKey,
-				},
-			},
-		},
-		PodAntiAffinity: &corev1.PodAntiAffinity{
-			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
-				{
-					LabelSelector: &metav1.LabelSelector{
-						MatchLabels: labels,
-					},
-					TopologyKey: *topologyKey,
-				},
-			},
-		},
-		NodeAffinity: &corev1.NodeAffinity{
-			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
-				NodeSelectorTerms: []corev1.NodeSelectorTerm{
-					{
-						MatchExpressions: []corev1.NodeSelectorRequirement{
-							{
-								Key:      *topologyKey,
-								Operator: corev1.NodeSelectorOpNotIn,
-								Values:   []string{""},
-							},
-						},
-					},
-				},
-			},
-		},
+	var constraints []corev1.TopologySpreadConstraint
+	zoned := false
+	for _, constraint := range p.TopologySpreadConstraints {
+		constraint := *constraint.DeepCopy()
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{MatchLabels: labels}
+		}
+		zoned = zoned || constraint.TopologyKey == ZoneLabel
+		constraints = append(constraints, constraint)
+	}
+
+	if p.ZoneSpread != nil && !zoned {
+		spread := corev1.TopologySpreadConstraint{
+			MaxSkew:           p.ZoneSpread.MaxSkew,
+			TopologyKey:       ZoneLabel,
+			WhenUnsatisfiable: p.ZoneSpread.WhenUnsatisfiable,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+		}
+		if spread.MaxSkew < 1 {
+			spread.MaxSkew = 1
+		}
+		if spread.WhenUnsatisfiable == "" {
+			spread.WhenUnsatisfiable = corev1.ScheduleAnyway
+		}
+		constraints = append(constraints, spread)
 	}
+	return constraints
 }
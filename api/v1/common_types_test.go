@@ -0,0 +1,188 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodAffinityAffinity(t *testing.T) {
+	labels := map[string]string{"app.kubernetes.io/instance": "mgr"}
+	zone := ZoneLabel
+	term := func(topologyKey string) corev1.PodAffinityTerm {
+		return corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: labels},
+			TopologyKey:   topologyKey,
+		}
+	}
+	nodeAffinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "disk", Operator: corev1.NodeSelectorOpIn, Values: []string{"ssd"}}},
+			}},
+		},
+	}
+	custom := &corev1.Affinity{
+		PodAffinity: &corev1.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term("kubernetes.io/hostname")},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		affinity *PodAffinity
+		want     *corev1.Affinity
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:     "preferred by default",
+			affinity: &PodAffinity{},
+			want: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: term("kubernetes.io/hostname")}},
+			}},
+		},
+		{
+			name:     "preferred",
+			affinity: &PodAffinity{AntiAffinity: AntiAffinityPreferred, TopologyKey: &zone},
+			want: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: term(zone)}},
+			}},
+		},
+		{
+			name:     "required",
+			affinity: &PodAffinity{AntiAffinity: AntiAffinityRequired},
+			want: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term("kubernetes.io/hostname")},
+			}},
+		},
+		{
+			name:     "disabled",
+			affinity: &PodAffinity{AntiAffinity: AntiAffinityDisabled},
+		},
+		{
+			name:     "disabled with node affinity",
+			affinity: &PodAffinity{AntiAffinity: AntiAffinityDisabled, NodeAffinity: nodeAffinity},
+			want:     &corev1.Affinity{NodeAffinity: nodeAffinity},
+		},
+		{
+			name:     "custom passthrough",
+			affinity: &PodAffinity{AntiAffinity: AntiAffinityRequired, NodeAffinity: nodeAffinity, Custom: custom},
+			want:     custom,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.affinity.Affinity(labels)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Affinity() = %+v, want %+v", got, tt.want)
+			}
+			if got == nil || tt.affinity.Custom != nil {
+				return
+			}
+
+			// the built affinity never attracts the pods it keeps apart, and selects them by their labels only
+			if got.PodAffinity != nil {
+				t.Errorf("Affinity() sets a pod affinity contradicting the anti-affinity: %+v", got.PodAffinity)
+			}
+			if anti := got.PodAntiAffinity; anti != nil {
+				terms := anti.RequiredDuringSchedulingIgnoredDuringExecution
+				for _, weighted := range anti.PreferredDuringSchedulingIgnoredDuringExecution {
+					terms = append(terms, weighted.PodAffinityTerm)
+				}
+				for _, term := range terms {
+					if len(term.LabelSelector.MatchExpressions) > 0 {
+						t.Errorf("Affinity() term selects pods by expressions: %+v", term.LabelSelector.MatchExpressions)
+					}
+				}
+			}
+		})
+	}
+
+	if got := (&PodAffinity{Custom: custom}).Affinity(labels); got == custom {
+		t.Error("Affinity() returns the custom affinity of the spec instead of a copy")
+	}
+}
+
+func TestPodAffinitySpreadConstraints(t *testing.T) {
+	labels := map[string]string{"app.kubernetes.io/instance": "mgr"}
+	selector := &metav1.LabelSelector{MatchLabels: labels}
+	own := &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "db"}}
+
+	tests := []struct {
+		name     string
+		affinity *PodAffinity
+		want     []corev1.TopologySpreadConstraint
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:     "no spread",
+			affinity: &PodAffinity{AntiAffinity: AntiAffinityRequired},
+		},
+		{
+			name:     "zone spread defaults",
+			affinity: &PodAffinity{ZoneSpread: &ZoneSpread{}},
+			want: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: ZoneLabel, WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: selector},
+			},
+		},
+		{
+			name:     "zone spread",
+			affinity: &PodAffinity{ZoneSpread: &ZoneSpread{MaxSkew: 2, WhenUnsatisfiable: corev1.DoNotSchedule}},
+			want: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 2, TopologyKey: ZoneLabel, WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: selector},
+			},
+		},
+		{
+			name: "constraints of the spec",
+			affinity: &PodAffinity{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.DoNotSchedule},
+				{MaxSkew: 3, TopologyKey: "rack", WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: own},
+			}},
+			want: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: selector},
+				{MaxSkew: 3, TopologyKey: "rack", WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: own},
+			},
+		},
+		{
+			name: "zone constraint of the spec replaces the zone spread",
+			affinity: &PodAffinity{
+				ZoneSpread: &ZoneSpread{},
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{MaxSkew: 2, TopologyKey: ZoneLabel, WhenUnsatisfiable: corev1.DoNotSchedule},
+				},
+			},
+			want: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 2, TopologyKey: ZoneLabel, WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: selector},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.affinity.SpreadConstraints(labels); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SpreadConstraints() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -33,33 +33,194 @@ type GroupReplicationClusterSpec struct {
 	ProxySpec         *Proxy                        `json:"proxy,omitempty"`
 	SchedulerBuckup   *SchedulerBuckup              `json:"schedulerBuckup,omitempty"`
 	MetricsCollection *MetricsCollection            `json:"metricsCollection,omitempty"`
+	// DeletionPolicy of the data of the members when the cluster is deleted
+	//+kubebuilder:default=Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// VolumeSnapshotClassName of the final snapshots of the Snapshot deletion policy,
+	// the default class of the CSI driver is used if empty
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+	// DisasterRecovery makes the group an asynchronous disaster recovery replica of another GroupReplicationCluster
+	DisasterRecovery *DisasterRecoverySpec `json:"disasterRecovery,omitempty"`
+	// Paused stops the reconciliation of the cluster, the owned resources, the labels and the replication
+	// of the members are left as they are and only the status is updated until it is unset
+	Paused bool `json:"paused,omitempty"`
+	// Hibernate scales the cluster to zero pods, the PersistentVolumeClaims, secrets and configMaps are kept.
+	// Once it is unset the members are started again with the size of the cluster and the group is bootstrapped
+	// from the member which has executed the most transactions
+	Hibernate bool `json:"hibernate,omitempty"`
+	// ConvertFrom seeds the group with the data and the GTIDs of an existing SingleInstance, its clients are moved
+	// to the primary of the group once the instance is read-only and the group has caught up. The connections open
+	// on the instance are not closed, the clients have to reconnect. It is only used when the cluster is created
+	ConvertFrom *ConvertFromSpec `json:"convertFrom,omitempty"`
+	// InitSQL are applied once on the primary after the group is first bootstrapped, so that they replicate
+	// to the secondaries. They are skipped if the data of the group is copied from a SingleInstance or a source group,
+	// or if they are added to a running group
+	InitSQL []InitSQLSource `json:"initSQL,omitempty"`
+}
+
+// ConvertFromSpec references the SingleInstance converted into the GroupReplicationCluster
+type ConvertFromSpec struct {
+	// SingleInstance is the name of the SingleInstance in the namespace of the cluster
+	SingleInstance string `json:"singleInstance"`
+}
+
+// ConversionPhase is the phase of the conversion of a SingleInstance into the group
+type ConversionPhase string
+
+const (
+	// ConversionCloning clones the SingleInstance into the seed member
+	ConversionCloning ConversionPhase = "Cloning"
+	// ConversionReplicating bootstraps the group from the seed member, which replicates from the SingleInstance
+	ConversionReplicating ConversionPhase = "Replicating"
+	// ConversionCuttingOver makes the SingleInstance read-only and routes its service to the primary of the group
+	ConversionCuttingOver ConversionPhase = "CuttingOver"
+	// ConversionCompleted means the group is writable and serves the clients of the SingleInstance
+	ConversionCompleted ConversionPhase = "Completed"
+	// ConversionFailed means the clone failed, the SingleInstance is left as it is
+	ConversionFailed ConversionPhase = "Failed"
+)
+
+// ConversionStatus defines the observed state of the conversion of a SingleInstance into the group
+type ConversionStatus struct {
+	Phase ConversionPhase `json:"phase,omitempty"`
+	// SeedMember is the member cloned from the SingleInstance, the group is bootstrapped from it
+	SeedMember string `json:"seedMember,omitempty"`
+	// Message describes the current step of the conversion
+	Message string `json:"message,omitempty"`
+}
+
+// DisasterRecoveryRole is the role of a disaster recovery group
+// +kubebuilder:validation:Enum=Replica;Primary
+type DisasterRecoveryRole string
+
+const (
+	// DisasterRecoveryReplica replicates from the source group and keeps the group read-only
+	DisasterRecoveryReplica DisasterRecoveryRole = "Replica"
+	// DisasterRecoveryPrimary stops replicating and makes the group writable
+	DisasterRecoveryPrimary DisasterRecoveryRole = "Primary"
+)
+
+// DisasterRecoverySpec defines the replication of a disaster recovery group from its source group
+type DisasterRecoverySpec struct {
+	// Source is the GroupReplicationCluster replicated from, the channel follows the primary of its group
+	Source SourceClusterReference `json:"source"`
+	// Role Replica replicates from the source group, Primary promotes the group: the channel is stopped
+	// and the group is writable. Setting Replica again demotes the group and resyncs it from the source group
+	//+kubebuilder:default=Replica
+	Role DisasterRecoveryRole `json:"role,omitempty"`
+	// IgnoreErrantTransactions demotes the group even if it has executed transactions the source group does not have
+	IgnoreErrantTransactions bool `json:"ignoreErrantTransactions,omitempty"`
+}
+
+// SourceClusterReference references the source GroupReplicationCluster
+type SourceClusterReference struct {
+	Name string `json:"name"`
+	// Namespace of the source, the namespace of the group if empty
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// DisasterRecoveryStatus defines the observed state of the disaster recovery replication
+type DisasterRecoveryStatus struct {
+	// Role is the role the group has been brought to
+	Role DisasterRecoveryRole `json:"role,omitempty"`
+	// Source is the writer endpoint of the source group
+	Source string `json:"source,omitempty"`
+	// ChannelState is the state of the replication channel on the primary of the group
+	ChannelState string `json:"channelState,omitempty"`
+	// SecondsBehind is the delay of the channel, -1 when it is unknown
+	SecondsBehind int64 `json:"secondsBehind,omitempty"`
+	// LastError is the last error of the channel, of the role change or of a secondary whose channel is not in sync
+	LastError string `json:"lastError,omitempty"`
+	// PromotedGTIDExecuted is the gtid_executed of the group when it was promoted,
+	// the transactions executed since then are checked against the source group on demotion
+	PromotedGTIDExecuted string `json:"promotedGTIDExecuted,omitempty"`
+	// ErrantTransactions are the transactions which block the demotion, the source group does not have them
+	ErrantTransactions string `json:"errantTransactions,omitempty"`
 }
 
 type Member struct {
 	Role MemberRole `json:"role,omitempty"`
-	Size *int32     `json:"size,omitempty"`
+	// Size is the number of members of the group. The PodDisruptionBudget of the group lets a drain evict
+	// (size-1)/2 members at once, a group of 1 or 2 members has no PodDisruptionBudget since it can not lose
+	// a member without losing its majority, draining the node of a member stops the group
+	Size *int32 `json:"size,omitempty"`
 }
 
 func (m *Member) GetSize() int32 {
-	count := int32(0)
 	if m.Size != nil {
-		count++
+		return *m.Size
 	}
-	return count
+	return 0
 }
 
 // GroupReplicationClusterStatus defines the observed state of GroupReplicationCluster
 type GroupReplicationClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	AccessPoint              string `json:"accessPoint,omitempty"`
-	Size                     int32  `json:"size,omitempty"`
-	Ready                    int32  `json:"ready,omitempty"`
-	Age                      string `json:"age,omitempty"`
+	AccessPoint string `json:"accessPoint,omitempty"`
+	// PrimaryAccessPoint is the read-write endpoint, routed to the current primary
+	PrimaryAccessPoint string `json:"primaryAccessPoint,omitempty"`
+	// ReplicasAccessPoint is the read-only endpoint, routed to the ONLINE secondaries
+	ReplicasAccessPoint string         `json:"replicasAccessPoint,omitempty"`
+	Size                int32          `json:"size,omitempty"`
+	Ready               int32          `json:"ready,omitempty"`
+	Age                 string         `json:"age,omitempty"`
+	Members             []MemberStatus `json:"members,omitempty"`
+	// DisasterRecovery is the state of the replication from the source group
+	DisasterRecovery *DisasterRecoveryStatus `json:"disasterRecovery,omitempty"`
+	// GroupName is the group_replication_group_name of the group, generated once when the cluster is created
+	GroupName string `json:"groupName,omitempty"`
+	// Conversion is the state of the conversion of the SingleInstance into the group
+	Conversion *ConversionStatus `json:"conversion,omitempty"`
+	// InitSQL is the state of the init sql scripts
+	InitSQL *InitSQLStatus `json:"initSQL,omitempty"`
+	// Conditions of the cluster
+	//+listType=map
+	//+listMapKey=type
+	Conditions               []metav1.Condition `json:"conditions,omitempty"`
 	appsv1.StatefulSetStatus `json:",inline"`
 }
 
+// MemberStatus defines the observed state of a group member
+type MemberStatus struct {
+	// Name is the name of the pod
+	Name string `json:"name"`
+	// Host is the report_host of the member
+	Host string `json:"host,omitempty"`
+	// Role is the role label of the pod, empty when the member is not ONLINE
+	Role string `json:"role,omitempty"`
+	// State is the MEMBER_STATE reported by replication_group_members
+	State string `json:"state,omitempty"`
+	// ServingReads is true when the secondary is part of the read-only service
+	ServingReads bool `json:"servingReads,omitempty"`
+	// TransactionsInQueue is the applier queue of the secondary
+	TransactionsInQueue int64 `json:"transactionsInQueue,omitempty"`
+	// SecondsBehind is the applier delay of the secondary
+	SecondsBehind int64 `json:"secondsBehind,omitempty"`
+	// Weight is the effective group_replication_member_weight of the member
+	Weight *int32 `json:"weight,omitempty"`
+	// LastError is the last replication error of an async replica
+	LastError string `json:"lastError,omitempty"`
+	// Volumes are the PersistentVolumeClaims of the member
+	Volumes []VolumeStatus `json:"volumes,omitempty"`
+}
+
+// VolumeStatus defines the observed state of a PersistentVolumeClaim of a member
+type VolumeStatus struct {
+	// Name of the PersistentVolumeClaim
+	Name string `json:"name"`
+	// Capacity is the size of the volume
+	Capacity string `json:"capacity,omitempty"`
+	// Requested is the size requested by the spec
+	Requested string `json:"requested,omitempty"`
+	// State is Ready, Expanding, FileSystemResizePending or ExpansionUnsupported
+	State string `json:"state,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.ready",description="The ready members of the GroupReplicationCluster"
+//+kubebuilder:printcolumn:name="Paused",type="boolean",JSONPath=".spec.paused",description="Whether the reconciliation of the GroupReplicationCluster is paused"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of the GroupReplicationCluster"
 
 // GroupReplicationCluster is the Schema for the GroupReplicationClusters API
 type GroupReplicationCluster struct {
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 19:40:12
+ * @file: replicaofgroupcluster_types.go
+ * @description: ReplicaofGroupCluster types, a source with GTID based async or semi-sync replicas
+ */
+
+// ReplicationMode defines how the source waits for the replicas
+// +kubebuilder:validation:Enum=Async;SemiSync
+type ReplicationMode string
+
+const (
+	// ReplicationModeAsync commits on the source without waiting for the replicas
+	ReplicationModeAsync ReplicationMode = "Async"
+	// ReplicationModeSemiSync commits on the source once a replica has received the transaction
+	ReplicationModeSemiSync ReplicationMode = "SemiSync"
+)
+
+// ReplicationSpec defines the replication between the source and the replicas
+type ReplicationSpec struct {
+	//+kubebuilder:default=Async
+	Mode ReplicationMode `json:"mode,omitempty"`
+	// AutoFailover promotes the most advanced replica when the source is unavailable
+	//+kubebuilder:default=true
+	AutoFailover *bool `json:"autoFailover,omitempty"`
+	// FailoverDelaySeconds is how long the source has to be unavailable before a replica is promoted
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:default=30
+	FailoverDelaySeconds *int32 `json:"failoverDelaySeconds,omitempty"`
+}
+
+// ReplicaofGroupClusterSpec defines the desired state of ReplicaofGroupCluster
+type ReplicaofGroupClusterSpec struct {
+	// Replicas is the number of replicas of the source
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:default=1
+	Replicas    *int32               `json:"replicas,omitempty"`
+	PodSpec     PodSpec              `json:"podSpec,omitempty"`
+	Ports       []corev1.ServicePort `json:"ports,omitempty"`
+	Type        corev1.ServiceType   `json:"type,omitempty"`
+	DnsPolicy   corev1.DNSPolicy     `json:"dnsPolicy,omitempty"`
+	Replication ReplicationSpec      `json:"replication,omitempty"`
+	// ReplicaLag removes the lagging replicas from the read-only service,
+	// only MaxSecondsBehind and RecoveryPercent apply to async replicas
+	ReplicaLag *ReplicaLagPolicy `json:"replicaLag,omitempty"`
+	// DeletionPolicy of the data of the members when the cluster is deleted
+	//+kubebuilder:default=Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// VolumeSnapshotClassName of the final snapshots of the Snapshot deletion policy,
+	// the default class of the CSI driver is used if empty
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// GetReplicas returns the number of replicas of the source
+func (s *ReplicaofGroupClusterSpec) GetReplicas() int32 {
+	if s.Replicas != nil {
+		return *s.Replicas
+	}
+	return 1
+}
+
+// GetSize returns the number of members, the source and its replicas
+func (s *ReplicaofGroupClusterSpec) GetSize() int32 {
+	return s.GetReplicas() + 1
+}
+
+// ReplicaofGroupClusterStatus defines the observed state of ReplicaofGroupCluster
+type ReplicaofGroupClusterStatus struct {
+	AccessPoint string `json:"accessPoint,omitempty"`
+	// PrimaryAccessPoint is the read-write endpoint, routed to the current source
+	PrimaryAccessPoint string `json:"primaryAccessPoint,omitempty"`
+	// ReplicasAccessPoint is the read-only endpoint, routed to the healthy replicas
+	ReplicasAccessPoint string `json:"replicasAccessPoint,omitempty"`
+	// Source is the pod name of the current source
+	Source string `json:"source,omitempty"`
+	// SourceUnavailableSince is when the source was first seen unavailable, empty while it is available
+	SourceUnavailableSince *metav1.Time   `json:"sourceUnavailableSince,omitempty"`
+	Size                   int32          `json:"size,omitempty"`
+	Ready                  int32          `json:"ready,omitempty"`
+	Members                []MemberStatus `json:"members,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Source",type="string",JSONPath=".status.source",description="The current source of the ReplicaofGroupCluster"
+//+kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".status.size",description="The size of the ReplicaofGroupCluster"
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.ready",description="The ready of the ReplicaofGroupCluster"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of the ReplicaofGroupCluster"
+
+// ReplicaofGroupCluster is the Schema for the ReplicaofGroupClusters API
+type ReplicaofGroupCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReplicaofGroupClusterSpec   `json:"spec,omitempty"`
+	Status ReplicaofGroupClusterStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ReplicaofGroupClusterList contains a list of ReplicaofGroupCluster
+type ReplicaofGroupClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReplicaofGroupCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ReplicaofGroupCluster{}, &ReplicaofGroupClusterList{})
+}
@@ -36,13 +36,33 @@ type SingleInstanceSpec struct {
 	// //+kubebuilder:validation:Enum=Sinlge;GroupReplicationCluster
 	// Category   GreatSqlType                  `json:"category,omitempty"`
 	// Role           MemberRole                    `json:"role,omitempty"`
-	Size           *int32                        `json:"size,omitempty"`
-	PodSpec        PodSpec                       `json:"podSpec,omitempty"`
-	Ports          []corev1.ServicePort          `json:"ports,omitempty"`
-	Type           corev1.ServiceType            `json:"type,omitempty"`
-	DnsPolicy      corev1.DNSPolicy              `json:"dnsPolicy,omitempty"`
-	UpgradeOptions UpgradeOptions                `json:"upgradeOptions,omitempty"`
+	// Size is always 1, a SingleInstance runs one pod on a statefulset
+	Size           *int32               `json:"size,omitempty"`
+	PodSpec        PodSpec              `json:"podSpec,omitempty"`
+	Ports          []corev1.ServicePort `json:"ports,omitempty"`
+	Type           corev1.ServiceType   `json:"type,omitempty"`
+	DnsPolicy      corev1.DNSPolicy     `json:"dnsPolicy,omitempty"`
+	UpgradeOptions UpgradeOptions       `json:"upgradeOptions,omitempty"`
+	// UpdateStrategy is ignored, the pod is always stopped before it is recreated
 	UpdateStrategy appsv1.DeploymentStrategyType `json:"updateStrategy,omitempty"`
+	// PodDisruptionBudget of the instance, no PodDisruptionBudget is created if empty
+	// since a single member can not be evicted without downtime anyway
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+	// DeletionPolicy of the data when the instance is deleted
+	//+kubebuilder:default=Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// VolumeSnapshotClassName of the final snapshot of the Snapshot deletion policy,
+	// the default class of the CSI driver is used if empty
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+	// Paused stops the reconciliation of the instance, the owned resources are left as they are
+	// and only the status is updated until it is unset
+	Paused bool `json:"paused,omitempty"`
+	// Hibernate scales the instance to zero pods, the PersistentVolumeClaims, secrets and configMaps are kept
+	// and the instance is started again with its size once it is unset
+	Hibernate bool `json:"hibernate,omitempty"`
+	// InitSQL are applied once on the instance after it is first initialized, the scripts typically
+	// create schemas, seed data and extra users. They are skipped if they are added to a running instance
+	InitSQL []InitSQLSource `json:"initSQL,omitempty"`
 }
 
 // GetSize returns the size of the SingleInstance
@@ -57,11 +77,26 @@ func (s *SingleInstanceSpec) GetSize() int32 {
 type SingleInstanceStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	AccessPoint             string `json:"accessPoint,omitempty"`
-	Size                    int32  `json:"size,omitempty"`
-	Ready                   int32  `json:"ready,omitempty"`
-	Age                     string `json:"age,omitempty"`
-	appsv1.DeploymentStatus `json:",inline"`
+	AccessPoint string `json:"accessPoint,omitempty"`
+	Size        int32  `json:"size,omitempty"`
+	// Ready is the number of pods which are ready and answer a SQL ping
+	Ready int32  `json:"ready,omitempty"`
+	Age   string `json:"age,omitempty"`
+	// ObservedGeneration is the generation of the spec the status was computed for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ServerVersion is the version reported by the running server
+	ServerVersion string `json:"serverVersion,omitempty"`
+	// LastError is the error of the last reconciliation, empty once a reconciliation succeeds
+	LastError string `json:"lastError,omitempty"`
+	// InitSQL is the state of the init sql scripts
+	InitSQL *InitSQLStatus `json:"initSQL,omitempty"`
+	// Conditions of the instance: Available, Progressing, Degraded, Paused and Hibernated
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// StatefulSetStatus is the status of the statefulset of the instance,
+	// its conditions and observedGeneration are replaced by the ones of the instance
+	appsv1.StatefulSetStatus `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
@@ -69,6 +104,8 @@ type SingleInstanceStatus struct {
 //+kubebuilder:printcolumn:name="AccessPoint",type="string",JSONPath=".status.accessPoint",description="The access point of the SingleInstance"
 //+kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".spec.size",description="The size of the SingleInstance"
 //+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.ready",description="The ready of the SingleInstance"
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.serverVersion",description="The server version of the SingleInstance",priority=1
+//+kubebuilder:printcolumn:name="Paused",type="boolean",JSONPath=".spec.paused",description="Whether the reconciliation of the SingleInstance is paused"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of the SingleInstance"
 
 // SingleInstance is the Schema for the singles API
@@ -38,6 +38,10 @@ import (
 
 	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
 	"github.com/gagraler/greatsql-operator/internal/controller"
+	"github.com/gagraler/greatsql-operator/internal/pkg/agent"
+	"github.com/gagraler/greatsql-operator/internal/pkg/health"
+	"github.com/gagraler/greatsql-operator/internal/pkg/lifecycle"
+	"github.com/gagraler/greatsql-operator/internal/pkg/toolbox"
 	"github.com/gagraler/greatsql-operator/internal/pkg/version"
 	"github.com/spf13/cobra"
 	//+kubebuilder:scaffold:imports
@@ -66,6 +70,12 @@ var (
 func init() {
 
 	rootCmd.AddCommand(version.VersionCmd)
+	rootCmd.AddCommand(health.HealthCheckCmd)
+	rootCmd.AddCommand(toolbox.InstallCmd)
+	rootCmd.AddCommand(toolbox.RenderConfigCmd)
+	rootCmd.AddCommand(toolbox.InitDataDirCmd)
+	rootCmd.AddCommand(agent.AgentCmd)
+	rootCmd.AddCommand(lifecycle.PreStopCmd)
 
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -155,8 +165,8 @@ func run() {
 	}
 
 	if err = (&controller.SingleInstanceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
 		Log:           ctrl.Log.WithName("controllers").WithName("SingleInstance"),
 		EventRecorder: mgr.GetEventRecorderFor("SingleInstance"),
 	}).SetupWithManager(mgr); err != nil {
@@ -172,6 +182,15 @@ func run() {
 		setupLog.Error(err, "unable to create controller", "controller", "GroupReplicationCluster")
 		os.Exit(1)
 	}
+	if err = (&controller.ReplicaofGroupClusterReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Log:           ctrl.Log.WithName("controllers").WithName("ReplicaofGroupCluster"),
+		EventRecorder: mgr.GetEventRecorderFor("ReplicaofGroupCluster"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ReplicaofGroupCluster")
+		os.Exit(1)
+	}
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&greatsqlv1.GroupReplicationCluster{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Info("webhook is not enbled")
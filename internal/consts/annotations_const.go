@@ -12,4 +12,11 @@ const (
 	// cm hash
 	ConfigMapDataHash string = "greatsql.cn/configmap-data-hash"
 	//UpdateOnChangeAnnotation  string = "greatsql.cn/update-on-change"
+	// hash of the last applied desired state, an applied object whose hash is unchanged
+	// but whose content differs has been edited out of band
+	AppliedHashAnnotation string = "greatsql.cn/applied-hash"
+	// maximum size of the auto-grow policy a claim has reached, the limit is reported once per maximum size
+	AutoGrowLimitAnnotation string = "greatsql.cn/auto-grow-limit"
+	// size a claim can not be expanded to since its storage class does not allow it, the size is reported once
+	ExpansionUnsupportedAnnotation string = "greatsql.cn/expansion-unsupported"
 )
@@ -14,12 +14,24 @@ const (
 	// 所以这里的DataDir不能直接使用 /data/GreatSQL
 	// 目前只发现在EKS上使用EBS存储会出现这个问题
 	DataDir string = "/data/"
+	// data subdir, the data dir of mysqld inside the data volume
+	DataSubDir string = DataDir + "GreatSQL"
+	// mysql os user, owner of the data dir
+	MySQLOSUser string = "mysql"
 	// error log dir
-	ErrorLogDir string = DataDir + "error.log"
+	ErrorLogDir string = DataDir + "GreatSQL/error.log"
 	// config dir
 	ConfigDir string = "/etc/"
 	// config file
 	ConfigFile string = "my.cnf"
+	// binlog dir, mount path of the binlog volume
+	BinlogDir string = "/binlog"
+	// redo dir, mount path of the redo volume
+	RedoDir string = "/redo"
+	// undo dir, mount path of the undo volume
+	UndoDir string = "/undo"
+	// tmp dir, mount path of the tmp volume
+	TmpDir string = "/tmpdir"
 )
 
 // greatsql port const
@@ -42,8 +54,62 @@ const (
 const (
 	Config   string = "config"
 	DB       string = "db"
+	Binlog   string = "binlog"
+	Redo     string = "redo"
+	Undo     string = "undo"
+	Tmp      string = "tmp"
 	Init     string = "init"
 	SnapPath string = "/snap"
+
+	// service name suffix
+	HeadlessServiceSuffix string = "-headless"
+	PrimaryServiceSuffix  string = "-primary"
+	ReplicasServiceSuffix string = "-replicas"
+
+	// final snapshot name suffix of the Snapshot deletion policy
+	FinalSnapshotSuffix string = "-final"
+)
+
+// greatsql operator tools const
+const (
+	// tools volume shared by the init container and the greatsql container
+	ToolsVolume string = "tools"
+	// tools dir, the operator binary is installed here by the init container
+	ToolsDir string = "/opt/greatsql/bin"
+	// tools binary name
+	ToolsBinary string = "greatsql-operator"
+	// operator image env, the image of the tools init container
+	OperatorImageEnv string = "OPERATOR_IMAGE"
+	// default operator image
+	DefaultOperatorImage string = "registry.cn-chengdu.aliyuncs.com/greatsql/greatsql-operator:latest"
+	// config template dir, mount path of the config template shared by the members of a group
+	ConfigTemplateDir string = "/etc/greatsql-template/"
+	// config render dir, my.cnf of the member is rendered here by the init container
+	ConfigRenderDir string = "/etc/greatsql-config/"
+	// config init container name
+	ConfigInitContainer string = "init-config"
+	// pod name env, set from the downward api
+	PodNameEnv string = "POD_NAME"
+	// data dir init container name
+	DataDirInitContainer string = "init-datadir"
+	// exit code of the data dir init container when the data dir is corrupted
+	DataDirCorruptedExitCode int32 = 3
+)
+
+// greatsql agent const
+const (
+	// agent sidecar container name
+	AgentContainerName string = "agent"
+	// agent port name
+	AgentPortName string = "agent"
+	// agent port
+	AgentPort int32 = 8090
+	// agent token env, the bearer token of the agent api
+	AgentTokenEnv string = "AGENT_TOKEN"
+	// agent secret name suffix
+	AgentSecretSuffix string = "-agent"
+	// agent token key of the agent secret
+	AgentTokenKey string = "token"
 )
 
 const (
@@ -12,4 +12,16 @@ const (
 	AppKubernetesComponent string = "app.kubernetes.io/component"
 	AppKubernetesName      string = "app.kubernetes.io/name"
 	AppKubernetesInstance  string = "app.kubernetes.io/instance"
+	// member role in the group, kept in sync with replication_group_members
+	RoleLabel string = "role"
+	// secondary is selected by the read-only service, lagging secondaries are excluded
+	ServingReadsLabel string = "greatsql.cn/serving-reads"
+	// uid of the PersistentVolumeClaim a final snapshot was taken of
+	SourceClaimUIDLabel string = "greatsql.cn/source-claim-uid"
+)
+
+// role label values
+const (
+	RolePrimary   string = "primary"
+	RoleSecondary string = "secondary"
 )
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/agent"
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+	"github.com/gagraler/greatsql-operator/internal/utils"
+	"github.com/go-logr/logr"
+)
+
+// ensureAgentSecret creates the secret holding the agent token of the instance
+// if it does not exist yet, and returns the token
+func ensureAgentSecret(ctx context.Context, c client.Client, owner client.Object, kind string, log logr.Logger) (string, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: kube.AgentSecretName(owner.GetName()), Namespace: owner.GetNamespace()}
+	err := c.Get(ctx, key, secret)
+	if err == nil {
+		return string(secret.Data[consts.AgentTokenKey]), nil
+	}
+	if !errors.IsNotFound(err) {
+		log.Error(err, "Unable to fetch agent secret", "Name", key.Name)
+		return "", err
+	}
+
+	owned := utils.CreateOwnerReference(owner, schema.GroupVersionKind{
+		Group:   greatsqlv1.GroupVersion.Group,
+		Version: greatsqlv1.GroupVersion.Version,
+		Kind:    kind,
+	})
+	secret = kube.NewAgentSecret(owner.GetName(), owner.GetNamespace(), utils.GetUUIDWithoutDashes(), owned)
+	if err := c.Create(ctx, secret); err != nil {
+		log.Error(err, "Could not create agent secret", "Name", key.Name)
+		return "", err
+	}
+	log.Info("Create agent secret is successful", "Name", secret.Name, "Namespace", secret.Namespace)
+	return string(secret.Data[consts.AgentTokenKey]), nil
+}
+
+// getAgentToken returns the token of the agent secret of the owner, empty if the secret does not exist yet
+func getAgentToken(ctx context.Context, c client.Client, owner client.Object) (string, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: kube.AgentSecretName(owner.GetName()), Namespace: owner.GetNamespace()}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	return string(secret.Data[consts.AgentTokenKey]), nil
+}
+
+// newAgentClient returns the client of the agent running beside the pod, empty if the pod has no ip yet
+func newAgentClient(pod *corev1.Pod, token string) *agent.Client {
+	ip := kube.GetPodIP(pod)
+	if ip == "" {
+		return nil
+	}
+	return agent.NewClient(ip, token)
+}
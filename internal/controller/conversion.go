@@ -0,0 +1,290 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/agent"
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+	"github.com/go-logr/logr"
+)
+
+// syncConversion converts the SingleInstance of the spec into the group in place, through the agents of the instance
+// and of the first member. The first member is cloned from the instance, the group is bootstrapped from it and it
+// replicates from the instance on a dedicated channel while the other members join the group. The instance is then
+// paused and made read-only, and once the group has applied every transaction of the instance its service is routed
+// to the primary of the group and the channel is removed. The connections already open on the instance are kept by
+// the service and fail to write, the clients have to reconnect to reach the group
+func (r *GroupReplicationClusterReconciler) syncConversion(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, members []greatsqlv1.MemberStatus, token string, log logr.Logger) (*greatsqlv1.ConversionStatus, error) {
+	if mgr.Spec.ConvertFrom == nil {
+		return mgr.Status.Conversion, nil
+	}
+	status := &greatsqlv1.ConversionStatus{SeedMember: fmt.Sprintf("%s-0", mgr.Name)}
+	if mgr.Status.Conversion != nil {
+		status = mgr.Status.Conversion.DeepCopy()
+	}
+	if status.Phase == greatsqlv1.ConversionCompleted || status.Phase == greatsqlv1.ConversionFailed {
+		return status, nil
+	}
+
+	si := &greatsqlv1.SingleInstance{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: mgr.Spec.ConvertFrom.SingleInstance, Namespace: mgr.Namespace}, si); err != nil {
+		if errors.IsNotFound(err) {
+			status.Message = fmt.Sprintf("SingleInstance %s not found", mgr.Spec.ConvertFrom.SingleInstance)
+			return status, nil
+		}
+		log.Error(err, "Unable to fetch the converted SingleInstance")
+		return status, err
+	}
+	siToken, err := getAgentToken(ctx, r.Client, si)
+	if err != nil {
+		log.Error(err, "Unable to fetch agent secret", "SingleInstance", si.Name)
+		return status, err
+	}
+	siPods, err := listMemberPods(ctx, r.Client, si.Namespace, si.Name)
+	if err != nil {
+		log.Error(err, "Could not list pods", "SingleInstance", si.Name)
+		return status, err
+	}
+	pods, err := listMemberPods(ctx, r.Client, mgr.Namespace, mgr.Name)
+	if err != nil {
+		log.Error(err, "Could not list member pods")
+		return status, err
+	}
+
+	siPod, seedPod := podByName(siPods, si.Name+"-0"), podByName(pods, status.SeedMember)
+	if siPod == nil || seedPod == nil || siPod.Status.Phase != corev1.PodRunning || seedPod.Status.Phase != corev1.PodRunning {
+		status.Message = fmt.Sprintf("Waiting for %s and %s to run", si.Name, status.SeedMember)
+		return status, nil
+	}
+	source, seed := newAgentClient(siPod, siToken), newAgentClient(seedPod, token)
+	if source == nil || seed == nil {
+		status.Message = fmt.Sprintf("Waiting for %s and %s to run", si.Name, status.SeedMember)
+		return status, nil
+	}
+	donor := mysql.CloneDonor{
+		Host: kube.GetPodFQDN(siPod.Name, si.Name+consts.HeadlessServiceSuffix, si.Namespace),
+		Port: consts.MysqlPort,
+	}
+	channel := mysql.ReplicationChannel{Name: mysql.ConversionChannel, Host: donor.Host, Port: donor.Port}
+
+	switch status.Phase {
+	case "":
+		for _, member := range members {
+			if member.Role != "" {
+				status.Phase = greatsqlv1.ConversionFailed
+				status.Message = "The group is already formed, only a new cluster is converted from a SingleInstance"
+				r.EventRecorder.Event(mgr, corev1.EventTypeWarning, "ConversionFailed", status.Message)
+				return status, nil
+			}
+		}
+		r.startClone(mgr, si, source, seed, donor, status, log)
+	case greatsqlv1.ConversionCloning:
+		r.syncClone(mgr, si, source, seed, donor, status, log)
+	case greatsqlv1.ConversionReplicating:
+		return status, r.syncConversionChannel(mgr, si, seed, pods, channel, token, status, log)
+	case greatsqlv1.ConversionCuttingOver:
+		return status, r.cutOver(ctx, mgr, si, source, seed, channel, status, log)
+	}
+	return status, nil
+}
+
+// startClone prepares the SingleInstance as the donor and starts to clone it into the seed member
+func (r *GroupReplicationClusterReconciler) startClone(mgr *greatsqlv1.GroupReplicationCluster, si *greatsqlv1.SingleInstance,
+	source, seed *agent.Client, donor mysql.CloneDonor, status *greatsqlv1.ConversionStatus, log logr.Logger) {
+	if _, err := source.PrepareCloneDonor(); err != nil {
+		log.Error(err, "Could not prepare the SingleInstance as clone donor", "SingleInstance", si.Name)
+		status.Message = fmt.Sprintf("Could not prepare %s as clone donor: %v", si.Name, err)
+		return
+	}
+	if err := seed.Clone(donor); err != nil {
+		log.Error(err, "Could not start the clone", "Pod", status.SeedMember)
+		status.Message = fmt.Sprintf("Could not clone %s into %s: %v", si.Name, status.SeedMember, err)
+		return
+	}
+	log.Info("Clone is started", "SingleInstance", si.Name, "Pod", status.SeedMember)
+	status.Phase = greatsqlv1.ConversionCloning
+	status.Message = fmt.Sprintf("%s is cloned from %s", status.SeedMember, si.Name)
+	r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "ConversionCloning", "%s is cloned from SingleInstance %s", status.SeedMember, si.Name)
+}
+
+// syncClone follows the clone of the seed member, the group is bootstrapped from it once mysqld has restarted
+// on the cloned data
+func (r *GroupReplicationClusterReconciler) syncClone(mgr *greatsqlv1.GroupReplicationCluster, si *greatsqlv1.SingleInstance,
+	source, seed *agent.Client, donor mysql.CloneDonor, status *greatsqlv1.ConversionStatus, log logr.Logger) {
+	stages, err := seed.GetCloneProgress()
+	if err != nil {
+		// mysqld is stopped to restart on the cloned data
+		status.Message = fmt.Sprintf("Waiting for %s to restart: %v", status.SeedMember, err)
+		return
+	}
+
+	switch mysql.CloneState(stages) {
+	case mysql.CloneStateFailed:
+		status.Phase = greatsqlv1.ConversionFailed
+		status.Message = fmt.Sprintf("Could not clone %s into %s: %v", si.Name, status.SeedMember, stages)
+		r.EventRecorder.Event(mgr, corev1.EventTypeWarning, "ConversionFailed", status.Message)
+	case mysql.CloneStateCompleted:
+		if _, err := seed.BootstrapGroup(); err != nil {
+			log.Error(err, "Could not bootstrap the group", "Pod", status.SeedMember)
+			r.EventRecorder.Eventf(mgr, corev1.EventTypeWarning, "GroupBootstrapFailed", "Could not bootstrap the group from %s: %v", status.SeedMember, err)
+			status.Message = fmt.Sprintf("Could not bootstrap the group from %s: %v", status.SeedMember, err)
+			return
+		}
+		log.Info("Bootstrap group is successful", "Pod", status.SeedMember)
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "GroupBootstrapped", "Group is bootstrapped from %s cloned from %s", status.SeedMember, si.Name)
+		status.Phase = greatsqlv1.ConversionReplicating
+		status.Message = fmt.Sprintf("%s replicates from %s", status.SeedMember, si.Name)
+	case "":
+		// the clone failed before it started, the agent logs the error
+		r.startClone(mgr, si, source, seed, donor, status, log)
+	default:
+		status.Message = fmt.Sprintf("%s is cloned from %s", status.SeedMember, si.Name)
+	}
+}
+
+// syncConversionChannel keeps the seed member replicating from the SingleInstance while the other members join
+// the group, the clients are moved once every member is ONLINE
+func (r *GroupReplicationClusterReconciler) syncConversionChannel(mgr *greatsqlv1.GroupReplicationCluster, si *greatsqlv1.SingleInstance,
+	seed *agent.Client, pods []corev1.Pod, channel mysql.ReplicationChannel, token string, status *greatsqlv1.ConversionStatus, log logr.Logger) error {
+	state, err := seed.GetChannelStatus(channel.Name)
+	if err != nil {
+		status.Message = fmt.Sprintf("Could not get the channel status of %s: %v", status.SeedMember, err)
+		return nil
+	}
+	if !state.Replica {
+		if state, err = seed.StartChannel(channel); err != nil {
+			log.Error(err, "Could not start the conversion channel", "Pod", status.SeedMember)
+			status.Message = fmt.Sprintf("Could not replicate %s from %s: %v", status.SeedMember, si.Name, err)
+			return nil
+		}
+		log.Info("Start conversion channel is successful", "Pod", status.SeedMember, "Source", channel.Host)
+	}
+
+	awake, err := r.wakeUpGroup(mgr, pods, token, log)
+	if err != nil {
+		return err
+	}
+	if !awake {
+		status.Message = "Waiting for the members to join the group"
+		return nil
+	}
+	if !state.IsRunning() {
+		status.Message = fmt.Sprintf("Channel of %s is %s: %s", status.SeedMember, state.State(), state.LastError)
+		return nil
+	}
+
+	status.Phase = greatsqlv1.ConversionCuttingOver
+	status.Message = fmt.Sprintf("The clients of %s are moved to the group", si.Name)
+	r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "ConversionCuttingOver", "All members are ONLINE, the clients of %s are moved to the group", si.Name)
+	return nil
+}
+
+// cutOver pauses the SingleInstance and makes it read-only first, so that the clients never write to both. Once the
+// group has applied every transaction of the instance its service is routed to the primary of the group, and the
+// channel is removed to make the group writable
+func (r *GroupReplicationClusterReconciler) cutOver(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, si *greatsqlv1.SingleInstance,
+	source, seed *agent.Client, channel mysql.ReplicationChannel, status *greatsqlv1.ConversionStatus, log logr.Logger) error {
+	if !si.Spec.Paused {
+		patch := client.MergeFrom(si.DeepCopy())
+		si.Spec.Paused = true
+		if err := r.Client.Patch(ctx, si, patch); err != nil {
+			log.Error(err, "Could not pause the SingleInstance", "SingleInstance", si.Name)
+			return err
+		}
+	}
+	sourceState, err := source.SetReadOnly()
+	if err != nil {
+		log.Error(err, "Could not make the SingleInstance read-only", "SingleInstance", si.Name)
+		status.Message = fmt.Sprintf("Could not make %s read-only: %v", si.Name, err)
+		return nil
+	}
+	seedState, err := seed.GetReplicaStatus()
+	if err != nil {
+		status.Message = fmt.Sprintf("Could not get gtid_executed of %s: %v", status.SeedMember, err)
+		return nil
+	}
+	missing, err := mysql.GTIDSubtract(sourceState.GTIDExecuted, seedState.GTIDExecuted)
+	if err != nil {
+		status.Message = fmt.Sprintf("Could not compare gtid_executed: %v", err)
+		return nil
+	}
+	if missing != "" {
+		status.Message = fmt.Sprintf("Waiting for the group to apply %s", missing)
+		return nil
+	}
+	if err := r.takeOverService(ctx, mgr, si, log); err != nil {
+		return err
+	}
+
+	if _, err := seed.PromoteChannel(channel); err != nil {
+		log.Error(err, "Could not remove the conversion channel", "Pod", status.SeedMember)
+		status.Message = fmt.Sprintf("Could not make the group writable: %v", err)
+		return nil
+	}
+	log.Info("Convert SingleInstance is successful", "SingleInstance", si.Name)
+	status.Phase = greatsqlv1.ConversionCompleted
+	status.Message = fmt.Sprintf("The group serves the clients of %s, which is paused and read-only and can be deleted", si.Name)
+	r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "ConversionCompleted", "The group is writable and serves the clients of %s, which have to reconnect", si.Name)
+	return nil
+}
+
+// takeOverService routes the service of the SingleInstance to the primary of the group, the service is controlled
+// by the group from then on so that it is kept once the SingleInstance is deleted
+func (r *GroupReplicationClusterReconciler) takeOverService(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, si *greatsqlv1.SingleInstance, log logr.Logger) error {
+	svc := &corev1.Service{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(si), svc); err != nil {
+		log.Error(err, "Unable to fetch the service of the SingleInstance", "SingleInstance", si.Name)
+		return err
+	}
+	if metav1.IsControlledBy(svc, mgr) {
+		return nil
+	}
+	primary := &corev1.Service{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: mgr.Name + consts.PrimaryServiceSuffix, Namespace: mgr.Namespace}, primary); err != nil {
+		log.Error(err, "Unable to fetch the primary service")
+		return err
+	}
+
+	svc.OwnerReferences = nil
+	if err := controllerutil.SetControllerReference(mgr, svc, r.Scheme); err != nil {
+		log.Error(err, "Could not set the owner of the service", "Name", svc.Name)
+		return err
+	}
+	svc.Spec.Selector = make(map[string]string, len(primary.Spec.Selector))
+	for key, value := range primary.Spec.Selector {
+		svc.Spec.Selector[key] = value
+	}
+	if err := r.Client.Update(ctx, svc); err != nil {
+		log.Error(err, "Could not route the service to the primary", "Name", svc.Name)
+		return err
+	}
+	log.Info("Service is routed to the primary of the group", "Name", svc.Name)
+	r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "ServiceCutOver", "Service %s is routed to the primary of the group", svc.Name)
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/go-logr/logr"
+)
+
+// ReasonDataDirCorrupted is the reason of the event recorded for a pod whose data dir is corrupted
+const ReasonDataDirCorrupted = "DataDirCorrupted"
+
+// dataDirFailures returns the pods whose data dir init container reported a corrupted data dir, a warning event
+// is recorded on the owner for each of them unless the Degraded condition of the owner already reports it.
+// mysqld is never started on such a data dir
+func dataDirFailures(recorder record.EventRecorder, owner client.Object, conditions []metav1.Condition, pods []corev1.Pod) []string {
+	reported := ""
+	if degraded := meta.FindStatusCondition(conditions, greatsqlv1.ConditionDegraded); degraded != nil &&
+		degraded.Status == metav1.ConditionTrue && degraded.Reason == ReasonDataDirCorrupted {
+		reported = degraded.Message
+	}
+
+	var failures []string
+	for i := range pods {
+		pod := &pods[i]
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name != consts.DataDirInitContainer {
+				continue
+			}
+			terminated := status.State.Terminated
+			if terminated == nil {
+				terminated = status.LastTerminationState.Terminated
+			}
+			if terminated == nil || terminated.ExitCode != consts.DataDirCorruptedExitCode {
+				continue
+			}
+			message := fmt.Sprintf("%s: %s", pod.Name, strings.TrimSpace(terminated.Message))
+			if !strings.Contains(reported, message) {
+				recorder.Event(owner, corev1.EventTypeWarning, ReasonDataDirCorrupted, "The data dir of "+message)
+			}
+			failures = append(failures, message)
+		}
+	}
+	return failures
+}
+
+// dataDirCondition returns the Degraded condition of the GroupReplicationCluster for the corrupted data dirs of its members,
+// nil keeps the condition if no data dir is corrupted and the condition was never set
+func dataDirCondition(mgr *greatsqlv1.GroupReplicationCluster, failures []string) *metav1.Condition {
+	if len(failures) > 0 {
+		condition := newCondition(mgr, greatsqlv1.ConditionDegraded, true, ReasonDataDirCorrupted, strings.Join(failures, "; "))
+		return &condition
+	}
+	if meta.FindStatusCondition(mgr.Status.Conditions, greatsqlv1.ConditionDegraded) == nil {
+		return nil
+	}
+	condition := newCondition(mgr, greatsqlv1.ConditionDegraded, false, "AsExpected", "The data dirs of the members are not corrupted")
+	return &condition
+}
+
+// checkDataDirs returns the members of the GroupReplicationCluster whose data dir is corrupted
+func (r *GroupReplicationClusterReconciler) checkDataDirs(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, log logr.Logger) ([]string, error) {
+	pods, err := listMemberPods(ctx, r.Client, mgr.Namespace, mgr.Name)
+	if err != nil {
+		log.Error(err, "Could not list member pods")
+		return nil, err
+	}
+	failures := dataDirFailures(r.EventRecorder, mgr, mgr.Status.Conditions, pods)
+	if len(failures) > 0 {
+		log.Info("Data dir of members is corrupted", "Failures", failures)
+	}
+	return failures, nil
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/agent"
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+	"github.com/go-logr/logr"
+)
+
+// sourceClusterKey returns the key of the source group of the disaster recovery group
+func sourceClusterKey(mgr *greatsqlv1.GroupReplicationCluster) client.ObjectKey {
+	key := client.ObjectKey{Name: mgr.Spec.DisasterRecovery.Source.Name, Namespace: mgr.Spec.DisasterRecovery.Source.Namespace}
+	if key.Namespace == "" {
+		key.Namespace = mgr.Namespace
+	}
+	return key
+}
+
+// syncDisasterRecovery brings the disaster recovery group to the role of its spec through the agent of its primary.
+// A replica group replicates from the writer endpoint of the source group on a dedicated channel which follows the
+// primary of the source group with asynchronous connection failover. The channel runs on the primary and is configured
+// on the secondaries as well, the member action mysql_start_failover_channels_if_primary starts it on the primary elected next.
+// Promoting stops the channel and makes the group writable, demoting makes it read-only and resyncs it from the source
+// group once the transactions written in between are known to the source group
+func (r *GroupReplicationClusterReconciler) syncDisasterRecovery(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, members []greatsqlv1.MemberStatus, token string, log logr.Logger) (*greatsqlv1.DisasterRecoveryStatus, error) {
+	if mgr.Spec.DisasterRecovery == nil {
+		return nil, nil
+	}
+	status := &greatsqlv1.DisasterRecoveryStatus{}
+	if mgr.Status.DisasterRecovery != nil {
+		status = mgr.Status.DisasterRecovery.DeepCopy()
+	}
+
+	primary, err := r.getPrimaryAgent(ctx, mgr.Namespace, mgr.Name, members, token)
+	if err != nil || primary == nil {
+		log.Info("Group primary is not available yet, keep the disaster recovery role", "error", err)
+		return status, nil
+	}
+
+	key := sourceClusterKey(mgr)
+	source := &greatsqlv1.GroupReplicationCluster{}
+	if err := r.Client.Get(ctx, key, source); err != nil {
+		log.Error(err, "Unable to fetch the source GroupReplicationCluster", "Source", key)
+		status.LastError = err.Error()
+		return status, nil
+	}
+	channel := mysql.ReplicationChannel{
+		Name:      mysql.DisasterRecoveryChannel,
+		Host:      fmt.Sprintf("%s%s.%s.svc.cluster.local", source.Name, consts.PrimaryServiceSuffix, source.Namespace),
+		Port:      consts.MysqlPort,
+		GroupName: groupName(source),
+	}
+	status.Source = channel.Host
+
+	state, err := primary.GetChannelStatus(channel.Name)
+	if err != nil {
+		log.Error(err, "Could not get the disaster recovery channel status")
+		status.LastError = err.Error()
+		return status, nil
+	}
+
+	switch mgr.Spec.DisasterRecovery.Role {
+	case greatsqlv1.DisasterRecoveryPrimary:
+		if status.Role != greatsqlv1.DisasterRecoveryPrimary || state.Replica || state.ReadOnly {
+			state, err = r.promoteDisasterRecovery(mgr, primary, channel, status, log)
+			if err != nil {
+				return status, nil
+			}
+		}
+	default:
+		if !state.IsReplicaOf(channel.Host, channel.Port) || !state.ReadOnly || !(state.IsRunning() || state.IORunning == "Connecting") {
+			if status.Role == greatsqlv1.DisasterRecoveryPrimary {
+				if !r.checkErrantTransactions(ctx, mgr, source, state, status, log) {
+					return status, nil
+				}
+			}
+			state, err = r.demoteDisasterRecovery(mgr, primary, channel, status, log)
+			if err != nil {
+				return status, nil
+			}
+		}
+	}
+
+	status.ChannelState = state.State()
+	if !state.Replica {
+		status.ChannelState = "Stopped"
+	}
+	status.SecondsBehind = state.SecondsBehind
+	status.LastError = state.LastError
+	r.syncSecondaryChannels(ctx, mgr, members, token, channel, mgr.Spec.DisasterRecovery.Role != greatsqlv1.DisasterRecoveryPrimary, status, log)
+	return status, nil
+}
+
+// syncSecondaryChannels configures the channel on the ONLINE secondaries of a replica group without starting it,
+// and removes it from the secondaries of a promoted group, so that the primary elected next follows the role.
+// A secondary which could not be brought to the role is reported in the last error
+func (r *GroupReplicationClusterReconciler) syncSecondaryChannels(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, members []greatsqlv1.MemberStatus,
+	token string, channel mysql.ReplicationChannel, configured bool, status *greatsqlv1.DisasterRecoveryStatus, log logr.Logger) {
+	pods, err := listMemberPods(ctx, r.Client, mgr.Namespace, mgr.Name)
+	if err != nil {
+		status.LastError = err.Error()
+		return
+	}
+	for _, member := range members {
+		if member.Role != consts.RoleSecondary {
+			continue
+		}
+		pod := podByName(pods, member.Name)
+		if pod == nil {
+			continue
+		}
+		secondary := newAgentClient(pod, token)
+		if secondary == nil {
+			continue
+		}
+		state, err := secondary.GetChannelStatus(channel.Name)
+		switch {
+		case err != nil:
+		case configured && !state.IsReplicaOf(channel.Host, channel.Port):
+			_, err = secondary.ConfigureChannel(channel)
+		case !configured && state.Replica:
+			_, err = secondary.RemoveChannel(channel)
+		}
+		if err != nil {
+			log.Error(err, "Could not sync the disaster recovery channel of the secondary", "Pod", member.Name)
+			status.LastError = fmt.Sprintf("channel of the secondary %s is not in sync: %v", member.Name, err)
+		}
+	}
+}
+
+// getPrimaryAgent returns the agent client of the primary of the group, nil if the group has no primary
+func (r *GroupReplicationClusterReconciler) getPrimaryAgent(ctx context.Context, namespace, instance string, members []greatsqlv1.MemberStatus, token string) (*agent.Client, error) {
+	pods, err := listMemberPods(ctx, r.Client, namespace, instance)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.Role != consts.RolePrimary {
+			continue
+		}
+		if pod := podByName(pods, member.Name); pod != nil {
+			return newAgentClient(pod, token), nil
+		}
+	}
+	return nil, nil
+}
+
+// promoteDisasterRecovery stops the channel and makes the group writable, the gtid_executed of the group
+// is recorded to find the transactions written while the group is promoted
+func (r *GroupReplicationClusterReconciler) promoteDisasterRecovery(mgr *greatsqlv1.GroupReplicationCluster, primary *agent.Client,
+	channel mysql.ReplicationChannel, status *greatsqlv1.DisasterRecoveryStatus, log logr.Logger) (mysql.ReplicaStatus, error) {
+	state, err := primary.PromoteChannel(channel)
+	if err != nil {
+		log.Error(err, "Could not promote the disaster recovery group")
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeWarning, "DisasterRecoveryPromoteFailed", "Could not promote the group: %v", err)
+		status.LastError = err.Error()
+		return state, err
+	}
+	if status.Role != greatsqlv1.DisasterRecoveryPrimary {
+		status.PromotedGTIDExecuted = state.GTIDExecuted
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "DisasterRecoveryPromoted", "Group stopped replicating from %s and is writable", channel.Host)
+	}
+	log.Info("Promote disaster recovery group is successful", "Source", channel.Host)
+	status.Role = greatsqlv1.DisasterRecoveryPrimary
+	status.ErrantTransactions = ""
+	return state, nil
+}
+
+// demoteDisasterRecovery makes the group read-only and points the channel of its primary to the source group
+func (r *GroupReplicationClusterReconciler) demoteDisasterRecovery(mgr *greatsqlv1.GroupReplicationCluster, primary *agent.Client,
+	channel mysql.ReplicationChannel, status *greatsqlv1.DisasterRecoveryStatus, log logr.Logger) (mysql.ReplicaStatus, error) {
+	state, err := primary.StartChannel(channel)
+	if err != nil {
+		log.Error(err, "Could not start the disaster recovery channel")
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeWarning, "DisasterRecoveryReplicaFailed", "Could not replicate from %s: %v", channel.Host, err)
+		status.LastError = err.Error()
+		return state, err
+	}
+	if status.Role != greatsqlv1.DisasterRecoveryReplica {
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "DisasterRecoveryReplicating", "Group is read-only and replicates from %s", channel.Host)
+	}
+	log.Info("Start disaster recovery channel is successful", "Source", channel.Host)
+	status.Role = greatsqlv1.DisasterRecoveryReplica
+	status.PromotedGTIDExecuted = ""
+	status.ErrantTransactions = ""
+	return state, nil
+}
+
+// checkErrantTransactions returns true if the source group has executed every transaction written while
+// the group was promoted, or if errant transactions are ignored, the errant transactions are reported otherwise
+func (r *GroupReplicationClusterReconciler) checkErrantTransactions(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, source *greatsqlv1.GroupReplicationCluster,
+	state mysql.ReplicaStatus, status *greatsqlv1.DisasterRecoveryStatus, log logr.Logger) bool {
+	if mgr.Spec.DisasterRecovery.IgnoreErrantTransactions {
+		return true
+	}
+	written, err := mysql.GTIDSubtract(state.GTIDExecuted, status.PromotedGTIDExecuted)
+	if err != nil {
+		status.LastError = err.Error()
+		return false
+	}
+	if written == "" {
+		return true
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: kube.AgentSecretName(source.Name), Namespace: source.Namespace}, secret); err != nil {
+		log.Error(err, "Unable to fetch the agent secret of the source group")
+		status.LastError = err.Error()
+		return false
+	}
+	sourcePrimary, err := r.getPrimaryAgent(ctx, source.Namespace, source.Name, source.Status.Members, string(secret.Data[consts.AgentTokenKey]))
+	if err != nil || sourcePrimary == nil {
+		status.LastError = fmt.Sprintf("primary of the source group %s is not available", source.Name)
+		return false
+	}
+	sourceState, err := sourcePrimary.GetReplicaStatus()
+	if err != nil {
+		status.LastError = err.Error()
+		return false
+	}
+
+	errant, err := mysql.GTIDSubtract(written, sourceState.GTIDExecuted)
+	if err != nil {
+		status.LastError = err.Error()
+		return false
+	}
+	if errant == "" {
+		return true
+	}
+	if status.ErrantTransactions != errant {
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeWarning, "ErrantTransactions",
+			"Group can not be demoted, the source group does not have the transactions %s", errant)
+	}
+	status.ErrantTransactions = errant
+	return false
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+	"github.com/go-logr/logr"
+)
+
+// defaultMemberWeight is the default group_replication_member_weight
+const defaultMemberWeight int32 = 50
+
+// electionWeight returns the weight of the member running in the zone, an arbitrator is never elected
+func electionWeight(election *greatsqlv1.ElectionSpec, member, zone string, arbitrator bool) int32 {
+	if arbitrator {
+		return 0
+	}
+	if weight, ok := election.MemberWeights[member]; ok {
+		return mysql.ClampWeight(weight)
+	}
+	if weight, ok := election.ZoneWeights[zone]; ok && zone != "" {
+		return mysql.ClampWeight(weight)
+	}
+	if election.DefaultWeight != nil {
+		return mysql.ClampWeight(*election.DefaultWeight)
+	}
+	return defaultMemberWeight
+}
+
+// syncMemberWeights sets the election weight of each running member live through its agent, the weight is
+// persisted so that a restarted member keeps it. The effective weight is reported in the status of the members,
+// the weights are only read while the cluster is paused or without an election spec
+func (r *GroupReplicationClusterReconciler) syncMemberWeights(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, members []greatsqlv1.MemberStatus, token string, log logr.Logger) error {
+	pods, err := listMemberPods(ctx, r.Client, mgr.Namespace, mgr.Name)
+	if err != nil {
+		log.Error(err, "Could not list member pods")
+		return err
+	}
+	election := mgr.Spec.ClusterSpec.Election
+
+	for i := range members {
+		member := &members[i]
+		pod := podByName(pods, member.Name)
+		if pod == nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		agentClient := newAgentClient(pod, token)
+		if agentClient == nil {
+			continue
+		}
+		current, err := agentClient.GetMemberWeight()
+		if err != nil {
+			log.Info("Could not get member weight", "Pod", pod.Name, "error", err)
+			continue
+		}
+
+		if election != nil && !mgr.Spec.Paused {
+			zone := ""
+			if pod.Spec.NodeName != "" {
+				node := &corev1.Node{}
+				if err := r.Client.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+					if client.IgnoreNotFound(err) != nil {
+						log.Error(err, "Unable to fetch node", "Node", pod.Spec.NodeName)
+						return err
+					}
+				}
+				zone = node.Labels[greatsqlv1.ZoneLabel]
+			}
+
+			desired := electionWeight(election, member.Name, zone, current.Arbitrator)
+			if desired != current.Weight {
+				if current, err = agentClient.SetMemberWeight(desired); err != nil {
+					log.Error(err, "Could not set member weight", "Pod", pod.Name, "Weight", desired)
+					r.EventRecorder.Eventf(mgr, corev1.EventTypeWarning, "MemberWeightFailed", "Could not set the weight of %s to %d: %v", pod.Name, desired, err)
+					continue
+				}
+				log.Info("Set member weight is successful", "Pod", pod.Name, "Weight", current.Weight, "Zone", zone)
+				r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "MemberWeightChanged", "Weight of %s is set to %d", pod.Name, current.Weight)
+			}
+		}
+
+		weight := current.Weight
+		member.Weight = &weight
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+	"github.com/gagraler/greatsql-operator/internal/utils"
+)
+
+// cleanupInterval is the interval to check the cleanup of a deleted instance
+const cleanupInterval = 10 * time.Second
+
+// the cleanups of each kind, run by the finalizer when an instance is deleted
+func init() {
+	utils.RegisterCleanup(consts.SingleInstance, utils.StageMembers, cleanupSingleInstanceMembers)
+	utils.RegisterCleanup(consts.SingleInstance, utils.StageStorage, cleanupSingleInstanceStorage)
+
+	utils.RegisterCleanup(consts.GroupReplicationCluster, utils.StageMembers, cleanupGroupReplicationClusterMembers)
+	utils.RegisterCleanup(consts.GroupReplicationCluster, utils.StageStorage, cleanupGroupReplicationClusterStorage)
+
+	utils.RegisterCleanup(consts.ReplicaofGroupCluster, utils.StageMembers, cleanupReplicaofGroupClusterMembers)
+	utils.RegisterCleanup(consts.ReplicaofGroupCluster, utils.StageStorage, cleanupReplicaofGroupClusterStorage)
+}
+
+// objectMeta returns the metadata of an object to delete
+func objectMeta(name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: namespace}
+}
+
+// cleanupSingleInstanceMembers deletes the statefulset, the deployment of an instance which has not been migrated yet,
+// and the resources they use, and waits for mysqld to be stopped. The service is kept once a GroupReplicationCluster
+// converted from the instance has taken it over
+func cleanupSingleInstanceMembers(ctx context.Context, cli client.Client, obj client.Object) error {
+	name, namespace := obj.GetName(), obj.GetNamespace()
+	objs := []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: objectMeta(name, namespace)},
+		&appsv1.Deployment{ObjectMeta: objectMeta(name, namespace)},
+		&corev1.Service{ObjectMeta: objectMeta(name+consts.HeadlessServiceSuffix, namespace)},
+		&corev1.ConfigMap{ObjectMeta: objectMeta(name+"-"+consts.Config, namespace)},
+		&policyv1.PodDisruptionBudget{ObjectMeta: objectMeta(name, namespace)},
+		&corev1.Secret{ObjectMeta: objectMeta(kube.AgentSecretName(name), namespace)},
+	}
+	svc := &corev1.Service{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, svc); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	} else if metav1.GetControllerOf(svc) == nil || metav1.IsControlledBy(svc, obj) {
+		objs = append(objs, svc)
+	}
+
+	if err := utils.DeleteObjects(ctx, cli, objs...); err != nil {
+		return err
+	}
+	return utils.WaitForPodsDeleted(ctx, cli, namespace, name)
+}
+
+// cleanupSingleInstanceStorage handles the data of the SingleInstance according to its deletion policy
+func cleanupSingleInstanceStorage(ctx context.Context, cli client.Client, obj client.Object) error {
+	instance := obj.(*greatsqlv1.SingleInstance)
+	return utils.FinalizePersistentVolumeClaims(ctx, cli, instance.Namespace, instance.Name,
+		instance.Spec.DeletionPolicy, instance.Spec.VolumeSnapshotClassName, kube.PersistentVolumeClaimName(instance.Name))
+}
+
+// cleanupGroupReplicationClusterMembers deletes the statefulset, the services, the configMaps
+// and the secrets of the GroupReplicationCluster, and waits for all members to be stopped
+func cleanupGroupReplicationClusterMembers(ctx context.Context, cli client.Client, obj client.Object) error {
+	mgr := obj.(*greatsqlv1.GroupReplicationCluster)
+	name, namespace := mgr.Name, mgr.Namespace
+
+	objs := []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: objectMeta(name, namespace)},
+		&corev1.Service{ObjectMeta: objectMeta(name+consts.HeadlessServiceSuffix, namespace)},
+		&corev1.Service{ObjectMeta: objectMeta(name+consts.PrimaryServiceSuffix, namespace)},
+		&corev1.Service{ObjectMeta: objectMeta(name+consts.ReplicasServiceSuffix, namespace)},
+		&policyv1.PodDisruptionBudget{ObjectMeta: objectMeta(name, namespace)},
+		&corev1.Secret{ObjectMeta: objectMeta(name+"-secret", namespace)},
+		&corev1.Secret{ObjectMeta: objectMeta(kube.AgentSecretName(name), namespace)},
+		&corev1.ConfigMap{ObjectMeta: objectMeta(name+"-"+consts.Config, namespace)},
+	}
+	// the per member configMaps of the clusters created before the members shared a config template
+	if len(mgr.Spec.Member) > 0 {
+		for ordinal := 1; ordinal <= int(mgr.Spec.Member[0].GetSize()); ordinal++ {
+			objs = append(objs, &corev1.ConfigMap{ObjectMeta: objectMeta(fmt.Sprintf("%s-config-%d", name, ordinal), namespace)})
+		}
+	}
+
+	if err := utils.DeleteObjects(ctx, cli, objs...); err != nil {
+		return err
+	}
+	return utils.WaitForPodsDeleted(ctx, cli, namespace, name)
+}
+
+// cleanupGroupReplicationClusterStorage handles the data of the members according to the deletion policy
+func cleanupGroupReplicationClusterStorage(ctx context.Context, cli client.Client, obj client.Object) error {
+	mgr := obj.(*greatsqlv1.GroupReplicationCluster)
+	return utils.FinalizePersistentVolumeClaims(ctx, cli, mgr.Namespace, mgr.Name,
+		mgr.Spec.DeletionPolicy, mgr.Spec.VolumeSnapshotClassName)
+}
+
+// cleanupReplicaofGroupClusterMembers deletes the statefulset, the services, the configMap and the agent secret
+// of the ReplicaofGroupCluster, and waits for the source and the replicas to be stopped
+func cleanupReplicaofGroupClusterMembers(ctx context.Context, cli client.Client, obj client.Object) error {
+	name, namespace := obj.GetName(), obj.GetNamespace()
+	if err := utils.DeleteObjects(ctx, cli,
+		&appsv1.StatefulSet{ObjectMeta: objectMeta(name, namespace)},
+		&corev1.Service{ObjectMeta: objectMeta(name+consts.HeadlessServiceSuffix, namespace)},
+		&corev1.Service{ObjectMeta: objectMeta(name+consts.PrimaryServiceSuffix, namespace)},
+		&corev1.Service{ObjectMeta: objectMeta(name+consts.ReplicasServiceSuffix, namespace)},
+		&corev1.ConfigMap{ObjectMeta: objectMeta(name+"-"+consts.Config, namespace)},
+		&corev1.Secret{ObjectMeta: objectMeta(kube.AgentSecretName(name), namespace)},
+	); err != nil {
+		return err
+	}
+	return utils.WaitForPodsDeleted(ctx, cli, namespace, name)
+}
+
+// cleanupReplicaofGroupClusterStorage handles the data of the members according to the deletion policy,
+// the claims of the statefulset are found by their instance label
+func cleanupReplicaofGroupClusterStorage(ctx context.Context, cli client.Client, obj client.Object) error {
+	roc := obj.(*greatsqlv1.ReplicaofGroupCluster)
+	return utils.FinalizePersistentVolumeClaims(ctx, cli, roc.Namespace, roc.Name,
+		roc.Spec.DeletionPolicy, roc.Spec.VolumeSnapshotClassName)
+}
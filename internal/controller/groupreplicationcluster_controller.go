@@ -18,12 +18,17 @@ package controller
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
+	"reflect"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -48,10 +53,17 @@ type GroupReplicationClusterReconciler struct {
 //+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=groupreplicationclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=groupreplicationclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=groupreplicationclusters/finalizers,verbs=update
-//+kubebuilder:rbac:groups=apps,resources=statefulset,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=singleinstances,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=core,resources=secret,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -77,107 +89,273 @@ func (r *GroupReplicationClusterReconciler) Reconcile(ctx context.Context, req c
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	finalizer := &utils.GreatSqlFinalizer{
+		Cli:      r.Client,
+		GreatSql: mgr,
+		Kind:     consts.GroupReplicationCluster,
+	}
+	if mgr.DeletionTimestamp != nil {
+		return r.handleFinalizer(finalizer, log)
+	}
+	if mgr.Spec.Paused {
+		return r.reconcilePaused(ctx, mgr, log)
+	}
+	if err := finalizer.AddFinalizer(); err != nil {
+		log.Error(err, "Could not add finalizer")
+		return ctrl.Result{}, err
+	}
+
+	token, err := ensureAgentSecret(ctx, r.Client, mgr, consts.GroupReplicationCluster, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	sts := &appsv1.StatefulSet{}
-	if err := r.Client.Get(ctx, req.NamespacedName, sts); err != nil {
+	stsErr := r.Client.Get(ctx, req.NamespacedName, sts)
+	if err := r.ensureGroupName(ctx, mgr, stsErr == nil, log); err != nil {
+		return ctrl.Result{}, err
+	}
+	if stsErr != nil {
 		if err := r.createResources(ctx, req, mgr, log); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
-	return ctrl.Result{}, nil
+	applier := kube.NewApplier(r.Client, r.Scheme, r.EventRecorder)
+	if err := r.applyResources(ctx, req, mgr, applier, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.createRoleServices(ctx, req, mgr, applier, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncPodDisruptionBudget(ctx, req, mgr, applier, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	dataDirs, err := r.checkDataDirs(ctx, mgr, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	members, err := r.syncMemberRoles(ctx, mgr, token, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncMemberWeights(ctx, mgr, members, token, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := autoGrowStorage(ctx, r.Client, r.EventRecorder, mgr, mgr.Spec.ClusterSpec.PodSpec, token, func(podName, volume string) string {
+		return statefulSetClaimName(kube.StorageVolumeName(mgr.Name, volume, kube.GroupVolumeOrdinal, true), podName)
+	}, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	conversion, err := r.syncConversion(ctx, mgr, members, token, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncPrimaryPlacement(ctx, mgr, members, token, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	disasterRecovery, err := r.syncDisasterRecovery(ctx, mgr, members, token, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncInitSQL(ctx, mgr, members, token, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	hibernation, err := r.syncHibernation(ctx, mgr, members, token, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateStatus(ctx, mgr, members, dataDirs, disasterRecovery, conversion, &hibernation, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: memberSyncInterval}, nil
 }
 
-// createResources creates the resources for the GroupReplicationCluster
-func (r *GroupReplicationClusterReconciler) createResources(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, log logr.Logger) error {
-	if err := r.createSecret(ctx, req, mgr, log); err != nil {
-		return err
+// reconcilePaused only updates the status of the paused GroupReplicationCluster, the resources, the labels
+// and the replication of the members are left as they are. The deletion of a paused cluster is still handled
+func (r *GroupReplicationClusterReconciler) reconcilePaused(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, log logr.Logger) (ctrl.Result, error) {
+	log.Info("Reconciliation is paused, only the status is updated")
+	token, err := getAgentToken(ctx, r.Client, mgr)
+	if err != nil {
+		log.Error(err, "Unable to fetch agent secret")
+		return ctrl.Result{}, err
 	}
 
-	size := mgr.Spec.Member[0].GetSize()
-	for ordinal := 1; ordinal <= int(size); ordinal++ {
-		if err := r.createConfigMap(ctx, req, mgr, log, ordinal); err != nil {
-			return err
-		}
+	members, err := r.syncMemberRoles(ctx, mgr, token, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
-		if err := r.createPersistentVolumeClaim(ctx, req, mgr, log, ordinal); err != nil {
-			return err
-		}
+	if err := r.syncMemberWeights(ctx, mgr, members, token, log); err != nil {
+		return ctrl.Result{}, err
+	}
 
-		if err := r.createStatefulSet(ctx, req, mgr, log, ordinal); err != nil {
-			return err
+	dataDirs, err := r.checkDataDirs(ctx, mgr, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateStatus(ctx, mgr, members, dataDirs, mgr.Status.DisasterRecovery, mgr.Status.Conversion, nil, log); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: memberSyncInterval}, nil
+}
+
+// handleFinalizer cleans up the deleted GroupReplicationCluster in order: proxy, members, then storage,
+// the deletion is requeued while the members are stopping or the final snapshots are in progress
+func (r *GroupReplicationClusterReconciler) handleFinalizer(finalizer *utils.GreatSqlFinalizer, log logr.Logger) (ctrl.Result, error) {
+	if err := finalizer.HandleFinalizer(); err != nil {
+		if goerrors.Is(err, utils.ErrCleanupInProgress) {
+			log.Info("Waiting for the cleanup to finish", "reason", err.Error())
+			return ctrl.Result{RequeueAfter: cleanupInterval}, nil
 		}
+		log.Error(err, "Could not handle finalizer")
+		r.EventRecorder.Event(finalizer.GreatSql, corev1.EventTypeWarning, "FinalizeFailed", err.Error())
+		return ctrl.Result{}, err
 	}
+	return ctrl.Result{}, nil
+}
 
-	return r.createService(ctx, req, mgr, log)
+// createResources creates the resources of the GroupReplicationCluster which are only created once
+func (r *GroupReplicationClusterReconciler) createResources(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, log logr.Logger) error {
+	return r.createSecret(ctx, req, mgr, log)
+}
+
+// applyResources applies the desired state of the resources owned by the GroupReplicationCluster,
+// out-of-band edits to them are reverted
+func (r *GroupReplicationClusterReconciler) applyResources(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, applier *kube.Applier, log logr.Logger) error {
+	if err := r.applyConfigMap(ctx, req, mgr, applier, log); err != nil {
+		return err
+	}
+
+	if err := r.applyStatefulSet(ctx, req, mgr, applier, log); err != nil {
+		return err
+	}
+
+	return r.applyService(ctx, req, mgr, applier, log)
 }
 
 // createSecret creates a Secret for the GroupReplicationCluster
 func (r *GroupReplicationClusterReconciler) createSecret(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, log logr.Logger) error {
 	secret := kube.NewSecretEnv(req.Name+"-secret", req.Namespace, mgr.Spec.ClusterSpec.PodSpec.Containers[0].Envs)
-	if err := r.Client.Create(ctx, secret); err != nil {
+	if err := r.Client.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
 		log.Error(err, "Could not create secret")
 		return err
 	}
 	return nil
 }
 
-// createConfigMap creates a ConfigMap for each member of the GroupReplicationCluster
-func (r *GroupReplicationClusterReconciler) createConfigMap(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, log logr.Logger, ordinal int) error {
-	configMapName := fmt.Sprintf("%s-config-%d", req.Name, ordinal)
-	existingConfigMap := &corev1.ConfigMap{}
-	err := r.Client.Get(ctx, client.ObjectKey{Name: configMapName, Namespace: req.Namespace}, existingConfigMap)
-	if err == nil {
-		log.Info("ConfigMap already exists", "Name", configMapName)
+// ensureGroupName generates the group name of the GroupReplicationCluster once and persists it in its status,
+// a running cluster keeps the group name of the config its members were started with
+func (r *GroupReplicationClusterReconciler) ensureGroupName(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, created bool, log logr.Logger) error {
+	if mgr.Status.GroupName != "" {
 		return nil
 	}
+	name := utils.GetUUID()
+	if created {
+		legacy, err := r.legacyGroupName(ctx, mgr)
+		if err != nil {
+			log.Error(err, "Could not read the group name of the members")
+			return err
+		}
+		// the members of a cluster created after the configMaps of the ordinals were dropped are started with its uid
+		name = string(mgr.UID)
+		if legacy != "" {
+			name = legacy
+		}
+	}
 
-	if !errors.IsNotFound(err) {
-		log.Error(err, "Unable to fetch ConfigMap")
+	mgr.Status.GroupName = name
+	if err := r.Client.Status().Update(ctx, mgr); err != nil {
+		log.Error(err, "Could not update status")
 		return err
 	}
+	log.Info("Group name is persisted", "GroupName", mgr.Status.GroupName)
+	return nil
+}
 
-	groupSeeds := []string{fmt.Sprintf("%s-%d.%s-headless.%s.svc.cluster.local:%d", req.Name, ordinal, req.Name, req.Namespace, consts.MgrCommunicatePort)}
+// legacyGroupName returns the group name of the config the members of a cluster created before the members shared
+// a config template were started with, all of them mounted the configMap of the first ordinal
+func (r *GroupReplicationClusterReconciler) legacyGroupName(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: fmt.Sprintf("%s-%s-%d", mgr.Name, consts.Config, 1), Namespace: mgr.Namespace}, configMap); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	variables, err := mysql.ParseMysqldSection(strings.NewReader(configMap.Data[consts.ConfigFile]))
+	if err != nil {
+		return "", err
+	}
+	for _, variable := range variables {
+		if variable[0] == "group_replication_group_name" {
+			return variable[1], nil
+		}
+	}
+	return "", nil
+}
+
+// groupName returns the group_replication_group_name of the GroupReplicationCluster
+func groupName(mgr *greatsqlv1.GroupReplicationCluster) string {
+	if mgr.Status.GroupName != "" {
+		return mgr.Status.GroupName
+	}
+	return string(mgr.UID)
+}
+
+// applyConfigMap applies the config template shared by the members of the GroupReplicationCluster, the server_id
+// and the pod name placeholders are replaced by the init container of each member
+func (r *GroupReplicationClusterReconciler) applyConfigMap(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, applier *kube.Applier, log logr.Logger) error {
+	configMapName := req.Name + "-" + consts.Config
+	headless := req.Name + consts.HeadlessServiceSuffix
+	var groupSeeds []string
+	for ordinal := 0; ordinal < int(mgr.Spec.Member[0].GetSize()); ordinal++ {
+		host := kube.GetPodFQDN(fmt.Sprintf("%s-%d", req.Name, ordinal), headless, req.Namespace)
+		groupSeeds = append(groupSeeds, fmt.Sprintf("%s:%d", host, consts.MgrCommunicatePort))
+	}
+	reportHost := kube.GetPodFQDN(mysql.PodNamePlaceholder, headless, req.Namespace)
 
 	memoryReq := mgr.Spec.ClusterSpec.PodSpec.Containers[0].Resources.Requests.Memory().Value()
 	cnf := new(mysql.MySQLConfig)
-	cnf.ServerID = fmt.Sprintf("%d", ordinal)
+	cnf.ServerID = mysql.ServerIDPlaceholder
 	cnf.EnableCluster = true
-	cnf.GroupReplicationGroupName = utils.GetUUID()
+	cnf.GroupReplicationGroupName = groupName(mgr)
+	cnf.GroupReplicationLocalAddress = fmt.Sprintf("%s:%d", reportHost, consts.MgrCommunicatePort)
 	cnf.GroupReplicationGroupSeeds = strings.Join(groupSeeds, ",")
-	cnf.ReportHost = fmt.Sprintf("%s-%d.%s-headless.%s.svc.cluster.local", req.Name, ordinal, req.Name, req.Namespace)
-	cnf.ReportPort = 3306
+	cnf.ReportHost = reportHost
+	cnf.ReportPort = int(consts.MysqlPort)
 	cnf.InnodbBufferPoolSize = mysql.CalculateInnodbBufferPoolSize(memoryReq)
+	cnf.StorageLayout = kube.StorageLayout(mgr.Spec.ClusterSpec.PodSpec)
 	data, err := cnf.String(*cnf)
 	if err != nil {
 		log.Error(err, "Could not get configMap data")
 		return err
 	}
 
-	configMap := kube.NewConfigMap(configMapName, req.Namespace, "my.cnf", data)
-	if err := r.Client.Create(ctx, configMap); err != nil {
-		log.Error(err, "Could not create configMap", "Name", configMapName)
+	configMap := kube.NewConfigMap(configMapName, req.Namespace, consts.ConfigFile, data)
+	if err := applier.Apply(ctx, mgr, configMap); err != nil {
+		log.Error(err, "Could not apply configMap", "Name", configMapName)
 		return err
 	}
-	log.Info("ConfigMap created successfully", "Name", configMapName, "Namespace", configMap.Namespace)
 	return nil
 }
 
-// createPersistentVolumeClaim creates a PersistentVolumeClaim for each member of the GroupReplicationCluster
-func (r *GroupReplicationClusterReconciler) createPersistentVolumeClaim(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, log logr.Logger, ordinal int) error {
-	pvc := kube.NewPersistentVolumeClaim(req.Name, req.Namespace, mgr.Spec.ClusterSpec.PodSpec)
-	pvc.Name = fmt.Sprintf("%s-%s-%d", req.Name, consts.DB, ordinal)
-	if err := r.Client.Create(ctx, pvc); err != nil {
-		log.Error(err, "Could not create persistentVolumeClaim")
-		return err
-	}
-	log.Info("Create persistentVolumeClaim is successful", "Name", pvc.Name, "Namespace", pvc.Namespace)
-	return nil
-}
-
-// createStatefulSet creates a StatefulSet for each member of the GroupReplicationCluster
-func (r *GroupReplicationClusterReconciler) createStatefulSet(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, log logr.Logger, ordinal int) error {
-	configMapName := fmt.Sprintf("%s-config-%d", req.Name, ordinal)
-	sts := kube.NewStatefulSet(configMapName, fmt.Sprintf("%s-headless", req.Name), mgr, ordinal)
+// applyStatefulSet applies the StatefulSet of the GroupReplicationCluster, the claims of the members
+// are expanded first when the storage size is increased
+func (r *GroupReplicationClusterReconciler) applyStatefulSet(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, applier *kube.Applier, log logr.Logger) error {
+	configMapName := req.Name + "-" + consts.Config
+	sts := kube.NewStatefulSet(configMapName, req.Name+consts.HeadlessServiceSuffix, mgr, mysql.ServerIDBase(groupName(mgr)))
 	sts.Spec.Template.Spec.Containers[0].Ports = append(sts.Spec.Template.Spec.Containers[0].Ports,
 		corev1.ContainerPort{
 			Name:          consts.MgrCommunicaName,
@@ -192,52 +370,135 @@ func (r *GroupReplicationClusterReconciler) createStatefulSet(ctx context.Contex
 			ContainerPort: consts.MysqlPort,
 			Protocol:      corev1.ProtocolTCP,
 		})
-	if err := r.Client.Create(ctx, sts); err != nil {
-		log.Error(err, "Could not create statefulSet")
+
+	recreating, err := expandStatefulSetVolumes(ctx, r.Client, r.EventRecorder, mgr, sts, log)
+	if err != nil || recreating {
 		return err
 	}
-	log.Info("Create statefulSet is successful", "Name", sts.Name, "Namespace", sts.Namespace)
+	if recreating, err := r.recreatePodManagementPolicy(ctx, mgr, sts, log); err != nil || recreating {
+		return err
+	}
+	if err := r.reportConfigVolumeRename(ctx, mgr, sts, log); err != nil {
+		return err
+	}
+	if err := applier.Apply(ctx, mgr, sts); err != nil {
+		log.Error(err, "Could not apply statefulSet")
+		return err
+	}
+	return nil
+}
+
+// recreatePodManagementPolicy deletes the statefulSet created with another pod management policy with orphan cascade,
+// the policy is immutable. It is applied again with the desired policy while the members keep running
+func (r *GroupReplicationClusterReconciler) recreatePodManagementPolicy(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, desired *appsv1.StatefulSet, log logr.Logger) (bool, error) {
+	live := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(desired), live); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if live.Spec.PodManagementPolicy == desired.Spec.PodManagementPolicy {
+		return false, nil
+	}
+
+	if err := r.Client.Delete(ctx, live, client.PropagationPolicy(metav1.DeletePropagationOrphan)); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "Could not delete statefulSet", "Name", live.Name)
+		return true, err
+	}
+	log.Info("Recreate the statefulSet with the new pod management policy", "Name", live.Name, "Policy", desired.Spec.PodManagementPolicy)
+	r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "StatefulSetRecreated",
+		"The statefulSet %s is recreated with the %s pod management policy", live.Name, desired.Spec.PodManagementPolicy)
+	return true, nil
+}
+
+// reportConfigVolumeRename reports the statefulSet whose members still mount the rendered config with the volume name
+// of the former per member configMaps. The volume name is part of the pod template, not of the immutable fields,
+// so the statefulSet is applied as is and its members mount the new volume as they are updated by the update strategy
+func (r *GroupReplicationClusterReconciler) reportConfigVolumeRename(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, desired *appsv1.StatefulSet, log logr.Logger) error {
+	live := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(desired), live); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	legacy := kube.StorageVolumeName(mgr.Name, consts.Config, kube.GroupVolumeOrdinal, true)
+	for _, volume := range live.Spec.Template.Spec.Volumes {
+		if volume.Name != legacy || volume.EmptyDir == nil {
+			continue
+		}
+		log.Info("Rename the rendered config volume of the statefulSet", "Name", live.Name, "From", legacy, "To", kube.RenderedConfigVolumeName(mgr.Name))
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "ConfigVolumeRenamed",
+			"The rendered config volume of %s is renamed to %s, the members mount it as they are updated", live.Name, kube.RenderedConfigVolumeName(mgr.Name))
+	}
 	return nil
 }
 
-// createService creates a Service for the GroupReplicationCluster
-func (r *GroupReplicationClusterReconciler) createService(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, log logr.Logger) error {
+// applyService applies the headless Service of the GroupReplicationCluster
+func (r *GroupReplicationClusterReconciler) applyService(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, applier *kube.Applier, log logr.Logger) error {
 	service := kube.NewService(req.Name, req.Namespace, consts.GroupReplicationCluster, &mgr.ObjectMeta, mgr.Spec.ClusterSpec.Ports, mgr.Spec.ClusterSpec.Type)
-	service.Name = fmt.Sprintf("%s-headless", req.Name)
+	service.Name = req.Name + consts.HeadlessServiceSuffix
 	service.Spec.ClusterIP = corev1.ClusterIPNone
-	if err := r.Client.Create(ctx, service); err != nil {
-		log.Error(err, "Could not create service")
+	// the members resolve each other by their FQDN before they are ONLINE, i.e. ready
+	service.Spec.PublishNotReadyAddresses = true
+	if err := applier.Apply(ctx, mgr, service); err != nil {
+		log.Error(err, "Could not apply service")
 		return err
 	}
-	log.Info("Create service is successful", "Name", service.Name, "Namespace", service.Namespace)
 	return nil
 }
 
-// initializeCluster initializes the GroupReplicationCluster
-func (r *GroupReplicationClusterReconciler) initializeCluster(mgr *greatsqlv1.GroupReplicationCluster) error {
-
-	mysql := mysql.MySQL{
-		Host:     fmt.Sprintf("%s.%s-headless.%s.svc.cluster.local", mgr.Name, mgr.Namespace, mgr.Namespace),
-		Port:     consts.MysqlPort,
-		UserName: consts.RootUser,
-		Password: consts.MySQLRootPassWord,
-		DB:       consts.MySQLDB,
+// syncPodDisruptionBudget keeps the PodDisruptionBudget of the GroupReplicationCluster in line with its size,
+// a drain never evicts more members than the group can lose without losing its majority. A group which can not
+// lose any member, i.e. of 1 or 2 members, has no PodDisruptionBudget since it would block every drain
+func (r *GroupReplicationClusterReconciler) syncPodDisruptionBudget(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, applier *kube.Applier, log logr.Logger) error {
+	maxUnavailable := kube.GroupMaxUnavailable(mgr.Spec.Member[0].GetSize())
+	if maxUnavailable.IntValue() == 0 {
+		return deletePodDisruptionBudget(ctx, r.Client, req.NamespacedName, log)
 	}
-	// create repl user
-	if err := mysql.CreateUser(consts.ReplicationChannelUser, consts.ReplicationChannelPassword); err != nil {
+	pdb := kube.NewPodDisruptionBudget(req.Name, req.Namespace, consts.GroupReplicationCluster, &mgr.ObjectMeta,
+		greatsqlv1.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable})
+	if err := applier.Apply(ctx, mgr, pdb); err != nil {
+		log.Error(err, "Could not apply podDisruptionBudget")
 		return err
 	}
+	return nil
+}
 
-	// grant repl user
-	if err := mysql.GrantPrivileges(consts.ReplicationChannelUser); err != nil {
-		return err
+// updateStatus updates the status of the GroupReplicationCluster, the Hibernated condition is kept if hibernation is nil.
+// The cluster is Degraded while the data dir of a member is corrupted
+func (r *GroupReplicationClusterReconciler) updateStatus(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, members []greatsqlv1.MemberStatus, dataDirs []string,
+	disasterRecovery *greatsqlv1.DisasterRecoveryStatus, conversion *greatsqlv1.ConversionStatus, hibernation *metav1.Condition, log logr.Logger) error {
+	status := mgr.Status.DeepCopy()
+	status.PrimaryAccessPoint = getServiceAccessPoint(ctx, r.Client, mgr.Name+consts.PrimaryServiceSuffix, mgr.Namespace)
+	status.ReplicasAccessPoint = getServiceAccessPoint(ctx, r.Client, mgr.Name+consts.ReplicasServiceSuffix, mgr.Namespace)
+	status.AccessPoint = status.PrimaryAccessPoint
+	status.Members = members
+	status.DisasterRecovery = disasterRecovery
+	status.Conversion = conversion
+	setPausedCondition(&status.Conditions, r.EventRecorder, mgr, mgr.Spec.Paused)
+	if hibernation != nil {
+		meta.SetStatusCondition(&status.Conditions, *hibernation)
+	}
+	if degraded := dataDirCondition(mgr, dataDirs); degraded != nil {
+		meta.SetStatusCondition(&status.Conditions, *degraded)
 	}
 
-	// set bootstrap member
-	if err := mysql.SetBootstrapMember(); err != nil {
-		return err
+	var ready int32
+	for _, member := range members {
+		if member.Role != "" {
+			ready++
+		}
+	}
+	status.Ready = ready
+	if len(mgr.Spec.Member) > 0 {
+		status.Size = mgr.Spec.Member[0].GetSize()
 	}
 
+	if reflect.DeepEqual(mgr.Status, *status) {
+		return nil
+	}
+
+	mgr.Status = *status
+	if err := r.Client.Status().Update(ctx, mgr); err != nil {
+		log.Error(err, "Could not update status")
+		return err
+	}
 	return nil
 }
 
@@ -246,5 +507,8 @@ func (r *GroupReplicationClusterReconciler) SetupWithManager(mgr ctrl.Manager) e
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&greatsqlv1.GroupReplicationCluster{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Complete(r)
 }
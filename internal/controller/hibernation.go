@@ -0,0 +1,203 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+	"github.com/go-logr/logr"
+)
+
+// reasons of the Hibernated condition
+const (
+	ReasonHibernating = "Hibernating"
+	ReasonHibernated  = "Hibernated"
+	ReasonWakingUp    = "WakingUp"
+	ReasonAwake       = "Awake"
+)
+
+// hibernationCondition returns the Hibernated condition of the owner scaled to zero while it hibernates,
+// an event is recorded once the last pod is stopped
+func hibernationCondition(recorder record.EventRecorder, owner client.Object, conditions []metav1.Condition, hibernate bool, running int) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               greatsqlv1.ConditionHibernated,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonAwake,
+		Message:            "The pods are running",
+		ObservedGeneration: owner.GetGeneration(),
+	}
+	if !hibernate {
+		return condition
+	}
+
+	condition.Reason = ReasonHibernating
+	condition.Message = fmt.Sprintf("%d pods are stopping", running)
+	if running == 0 {
+		if !meta.IsStatusConditionTrue(conditions, greatsqlv1.ConditionHibernated) {
+			recorder.Event(owner, corev1.EventTypeNormal, "Hibernated", "All pods are stopped, the data is kept")
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonHibernated
+		condition.Message = "All pods are stopped, the data is kept"
+	}
+	return condition
+}
+
+// syncHibernation returns the Hibernated condition of the GroupReplicationCluster. The statefulset is scaled to zero
+// while the cluster hibernates. Once it wakes up the members are started with the start_on_boot of my.cnf but have
+// no group to join, the group is bootstrapped again from the member which has executed the most transactions and
+// the other members are started once it is ONLINE
+func (r *GroupReplicationClusterReconciler) syncHibernation(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, members []greatsqlv1.MemberStatus, token string, log logr.Logger) (metav1.Condition, error) {
+	pods, err := listMemberPods(ctx, r.Client, mgr.Namespace, mgr.Name)
+	if err != nil {
+		log.Error(err, "Could not list member pods")
+		return metav1.Condition{}, err
+	}
+	condition := hibernationCondition(r.EventRecorder, mgr, mgr.Status.Conditions, mgr.Spec.Hibernate, len(pods))
+	if mgr.Spec.Hibernate {
+		return condition, nil
+	}
+
+	// a new cluster, or one whose members all stopped, has no group to join either and is bootstrapped the same way.
+	// A converted cluster is bootstrapped from its seed member by the conversion
+	previous := meta.FindStatusCondition(mgr.Status.Conditions, greatsqlv1.ConditionHibernated)
+	if previous == nil || previous.Reason == ReasonAwake {
+		if converting(mgr) || hasGroup(members) {
+			return condition, nil
+		}
+		_, err := r.wakeUpGroup(mgr, pods, token, log)
+		return condition, err
+	}
+
+	awake, err := r.wakeUpGroup(mgr, pods, token, log)
+	if err != nil {
+		return condition, err
+	}
+	if !awake {
+		condition.Reason = ReasonWakingUp
+		condition.Message = "The group is being bootstrapped"
+		return condition, nil
+	}
+	log.Info("Wake up GroupReplicationCluster is successful")
+	r.EventRecorder.Event(mgr, corev1.EventTypeNormal, "WokeUp", "All members are ONLINE in the group")
+	return condition, nil
+}
+
+// wakeUpGroup bootstraps the group from the member which has executed the most transactions once every member
+// is running, the members which are not part of the group are started once it is bootstrapped. The statefulset
+// starts the members in parallel, none of them is ready before the group is bootstrapped.
+// It returns true once every member is ONLINE
+func (r *GroupReplicationClusterReconciler) wakeUpGroup(mgr *greatsqlv1.GroupReplicationCluster, pods []corev1.Pod, token string, log logr.Logger) (bool, error) {
+	size := int(mgr.Spec.Member[0].GetSize())
+	names := make([]string, 0, size)
+	for ordinal := 0; ordinal < size; ordinal++ {
+		names = append(names, fmt.Sprintf("%s-%d", mgr.Name, ordinal))
+	}
+
+	states := make(map[string]string, len(pods))
+	gtids := make(map[string]string, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		agentClient := newAgentClient(pod, token)
+		if agentClient == nil {
+			continue
+		}
+		members, err := agentClient.GetGroupMembers()
+		if err != nil {
+			log.Info("Could not get group members", "Pod", pod.Name, "error", err)
+			continue
+		}
+		states[pod.Name] = mysql.MemberStateOffline
+		for _, member := range members {
+			if memberPodName(member.Host) == pod.Name {
+				states[pod.Name] = member.State
+			}
+		}
+		if state := states[pod.Name]; state == mysql.MemberStateOnline || state == mysql.MemberStateRecovering {
+			continue
+		}
+
+		status, err := agentClient.GetReplicaStatus()
+		if err != nil {
+			log.Info("Could not get gtid_executed", "Pod", pod.Name, "error", err)
+			continue
+		}
+		gtids[pod.Name] = status.GTIDExecuted
+	}
+
+	plan := mysql.PlanGroupStart(names, states, gtids)
+	switch {
+	case len(plan.Join) > 0 || plan.Online:
+		for _, name := range plan.Join {
+			if _, err := newAgentClient(podByName(pods, name), token).StartGroupReplication(); err != nil {
+				log.Info("Could not start group replication", "Pod", name, "error", err)
+				continue
+			}
+			log.Info("Start group replication is successful", "Pod", name)
+		}
+		return plan.Online, nil
+	case len(plan.Waiting) > 0:
+		// every member has to be known, the one which is not running yet may have the most transactions
+		log.Info("Waiting for the members to start before the group is bootstrapped", "Running", len(gtids), "Size", size, "Waiting", plan.Waiting)
+		return false, nil
+	case plan.Diverged:
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeWarning, "GroupBootstrapBlocked",
+			"Group can not be bootstrapped, the members have diverged transactions: %v", gtids)
+		return false, nil
+	case plan.Bootstrap == "":
+		// a member is part of the group but none is ONLINE or waiting to join yet
+		return false, nil
+	}
+
+	candidate := plan.Bootstrap
+	if _, err := newAgentClient(podByName(pods, candidate), token).BootstrapGroup(); err != nil {
+		log.Error(err, "Could not bootstrap the group", "Pod", candidate)
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeWarning, "GroupBootstrapFailed", "Could not bootstrap the group from %s: %v", candidate, err)
+		return false, err
+	}
+	log.Info("Bootstrap group is successful", "Pod", candidate, "GTIDExecuted", gtids[candidate])
+	r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "GroupBootstrapped",
+		"Group is bootstrapped from %s which has executed the most transactions", candidate)
+	return false, nil
+}
+
+// converting returns true while the group is converted from a SingleInstance
+func converting(mgr *greatsqlv1.GroupReplicationCluster) bool {
+	return mgr.Spec.ConvertFrom != nil && (mgr.Status.Conversion == nil || mgr.Status.Conversion.Phase != greatsqlv1.ConversionCompleted)
+}
+
+// hasGroup returns true if a member has a role in the group
+func hasGroup(members []greatsqlv1.MemberStatus) bool {
+	for _, member := range members {
+		if member.Role != "" {
+			return true
+		}
+	}
+	return false
+}
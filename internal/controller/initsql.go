@@ -0,0 +1,213 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/agent"
+	"github.com/go-logr/logr"
+)
+
+// loadInitSQL returns the names and the content of the sql files of the sources, sorted by key within each source
+func loadInitSQL(ctx context.Context, c client.Client, namespace string, sources []greatsqlv1.InitSQLSource) ([]string, []string, error) {
+	var names, scripts []string
+	for _, source := range sources {
+		var kind, name string
+		data := map[string]string{}
+		switch {
+		case source.ConfigMapRef != nil:
+			configMap := &corev1.ConfigMap{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.ConfigMapRef.Name}, configMap); err != nil {
+				return nil, nil, err
+			}
+			kind, name, data = "configmap", configMap.Name, configMap.Data
+		case source.SecretRef != nil:
+			secret := &corev1.Secret{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.SecretRef.Name}, secret); err != nil {
+				return nil, nil, err
+			}
+			kind, name = "secret", secret.Name
+			for key, value := range secret.Data {
+				data[key] = string(value)
+			}
+		default:
+			return nil, nil, fmt.Errorf("init sql source references neither a configMap nor a secret")
+		}
+
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			names = append(names, fmt.Sprintf("%s/%s/%s", kind, name, key))
+			scripts = append(scripts, data[key])
+		}
+	}
+	return names, scripts, nil
+}
+
+// applyInitSQL starts the init sql scripts of the owner once on the primary and polls their progress on the next
+// reconciliations. The Running phase is saved before they are started and the start time once the primary accepted
+// them, a start which failed is retried and a run interrupted once accepted is never applied again.
+// An instance which became available without scripts records them as skipped, scripts added later are not applied.
+// A nil primary waits for the instance to be available
+func applyInitSQL(ctx context.Context, c client.Client, recorder record.EventRecorder, owner client.Object, sources []greatsqlv1.InitSQLSource,
+	current *greatsqlv1.InitSQLStatus, primary *agent.Client, save func(*greatsqlv1.InitSQLStatus) error, log logr.Logger) error {
+	if current != nil && current.Phase != greatsqlv1.InitSQLRunning || primary == nil {
+		return nil
+	}
+	if current != nil && current.StartTime != nil {
+		return pollInitSQL(recorder, owner, current, primary, save, log)
+	}
+	if current == nil && len(sources) == 0 {
+		return save(&greatsqlv1.InitSQLStatus{Phase: greatsqlv1.InitSQLSkipped, Message: "The instance was initialized without init sql scripts"})
+	}
+
+	names, scripts, err := loadInitSQL(ctx, c, owner.GetNamespace(), sources)
+	if err != nil {
+		log.Error(err, "Could not load the init sql scripts")
+		return err
+	}
+	if current == nil {
+		if err := save(&greatsqlv1.InitSQLStatus{Phase: greatsqlv1.InitSQLRunning}); err != nil {
+			return err
+		}
+	}
+	// the agent applies the scripts at most once, a start whose response was lost returns the progress of the first one
+	if _, err := primary.StartInitSQL(names, scripts); err != nil {
+		log.Error(err, "Could not start the init sql scripts, the start is retried")
+		return err
+	}
+	now := metav1.Now()
+	log.Info("Init sql scripts are started", "Scripts", names)
+	return save(&greatsqlv1.InitSQLStatus{Phase: greatsqlv1.InitSQLRunning, StartTime: &now})
+}
+
+// pollInitSQL saves the progress of the running init sql scripts, a primary which has not started them was restarted
+// or replaced during the run and the scripts are not applied again
+func pollInitSQL(recorder record.EventRecorder, owner client.Object, current *greatsqlv1.InitSQLStatus, primary *agent.Client,
+	save func(*greatsqlv1.InitSQLStatus) error, log logr.Logger) error {
+	progress, err := primary.GetInitSQLProgress()
+	if err != nil {
+		log.Error(err, "Could not get the progress of the init sql scripts")
+		return err
+	}
+
+	status := current.DeepCopy()
+	status.Scripts = progress.Scripts
+	switch {
+	case !progress.Started:
+		status.Phase = greatsqlv1.InitSQLFailed
+		status.Message = "The scripts were interrupted, they are not applied again"
+		recorder.Event(owner, corev1.EventTypeWarning, "InitSQLFailed", status.Message)
+	case progress.Running:
+		if reflect.DeepEqual(status, current) {
+			return nil
+		}
+	case progress.Error != "":
+		log.Info("Init sql script failed", "Error", progress.Error)
+		status.Phase = greatsqlv1.InitSQLFailed
+		status.Message = progress.Error
+		recorder.Eventf(owner, corev1.EventTypeWarning, "InitSQLFailed", "Could not apply %s, the scripts are not applied again", progress.Error)
+	default:
+		now := metav1.Now()
+		status.Phase = greatsqlv1.InitSQLCompleted
+		status.CompletionTime = &now
+		log.Info("Init sql scripts are applied", "Scripts", progress.Scripts)
+		recorder.Eventf(owner, corev1.EventTypeNormal, "InitSQLCompleted", "Applied %d init sql scripts", len(progress.Scripts))
+	}
+	return save(status)
+}
+
+// syncInitSQL applies the init sql scripts on the primary of the GroupReplicationCluster once the group is bootstrapped,
+// they are skipped if the data of the group is copied from a SingleInstance or from a source group
+func (r *GroupReplicationClusterReconciler) syncInitSQL(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, members []greatsqlv1.MemberStatus, token string, log logr.Logger) error {
+	save := func(status *greatsqlv1.InitSQLStatus) error {
+		mgr.Status.InitSQL = status
+		if err := r.Client.Status().Update(ctx, mgr); err != nil {
+			log.Error(err, "Could not update status")
+			return err
+		}
+		return nil
+	}
+	current := mgr.Status.InitSQL
+	if current != nil && current.Phase != greatsqlv1.InitSQLRunning {
+		return nil
+	}
+
+	if current == nil && len(mgr.Spec.InitSQL) > 0 {
+		switch {
+		case mgr.Spec.ConvertFrom != nil:
+			return save(&greatsqlv1.InitSQLStatus{Phase: greatsqlv1.InitSQLSkipped, Message: "The data of the group is converted from a SingleInstance"})
+		case mgr.Spec.DisasterRecovery != nil:
+			return save(&greatsqlv1.InitSQLStatus{Phase: greatsqlv1.InitSQLSkipped, Message: "The data of the group is replicated from the source group"})
+		}
+	}
+
+	primary, err := r.getPrimaryAgent(ctx, mgr.Namespace, mgr.Name, members, token)
+	if err != nil {
+		log.Error(err, "Could not list member pods")
+		return err
+	}
+	return applyInitSQL(ctx, r.Client, r.EventRecorder, mgr, mgr.Spec.InitSQL, current, primary, save, log)
+}
+
+// syncInitSQL applies the init sql scripts on the SingleInstance once its pod is ready
+func (r *SingleInstanceReconciler) syncInitSQL(ctx context.Context, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
+	current := SingleInstance.Status.InitSQL
+	if current != nil && current.Phase != greatsqlv1.InitSQLRunning {
+		return nil
+	}
+	save := func(status *greatsqlv1.InitSQLStatus) error {
+		SingleInstance.Status.InitSQL = status
+		if err := r.Client.Status().Update(ctx, SingleInstance); err != nil {
+			log.Error(err, "Could not update status")
+			return err
+		}
+		return nil
+	}
+
+	token, err := ensureAgentSecret(ctx, r.Client, SingleInstance, consts.SingleInstance, log)
+	if err != nil {
+		return err
+	}
+	pods, err := listMemberPods(ctx, r.Client, SingleInstance.Namespace, SingleInstance.Name)
+	if err != nil {
+		log.Error(err, "Could not list pods")
+		return err
+	}
+	var primary *agent.Client
+	for i := range pods {
+		if isPodReady(&pods[i]) {
+			primary = newAgentClient(&pods[i], token)
+			break
+		}
+	}
+	return applyInitSQL(ctx, r.Client, r.EventRecorder, SingleInstance, SingleInstance.Spec.InitSQL, current, primary, save, log)
+}
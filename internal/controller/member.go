@@ -0,0 +1,270 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+	"github.com/gagraler/greatsql-operator/internal/utils"
+	"github.com/go-logr/logr"
+)
+
+// memberSyncInterval is the interval to resync the group membership,
+// the role labels and the read-write / read-only services follow it
+const memberSyncInterval = 10 * time.Second
+
+// memberPodName returns the pod name of the member, the report_host is the pod FQDN
+func memberPodName(host string) string {
+	return strings.SplitN(host, ".", 2)[0]
+}
+
+// listMemberPods lists the pods of the instance sorted by name
+func listMemberPods(ctx context.Context, c client.Client, namespace, instance string) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{
+		consts.AppKubernetesInstance: instance,
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	return pods.Items, nil
+}
+
+// getGroupMembers returns the group membership seen by the first reachable ONLINE member of a group which has
+// an ONLINE primary, keyed by pod name. It returns nil if no member sees such a group
+func (r *GroupReplicationClusterReconciler) getGroupMembers(pods []corev1.Pod, token string) (map[string]mysql.GroupMember, error) {
+	var lastErr error
+	for i := range pods {
+		if pods[i].Status.Phase != corev1.PodRunning {
+			continue
+		}
+		agentClient := newAgentClient(&pods[i], token)
+		if agentClient == nil {
+			continue
+		}
+		members, err := agentClient.GetGroupMembers()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		group := make(map[string]mysql.GroupMember, len(members))
+		hasPrimary := false
+		for _, member := range members {
+			if member.Host == "" {
+				continue
+			}
+			group[memberPodName(member.Host)] = member
+			hasPrimary = hasPrimary || member.IsPrimary()
+		}
+		// a member which is not ONLINE in the group only sees itself, its view would strip the labels of the others
+		if reporter, ok := group[pods[i].Name]; ok && reporter.IsOnline() && hasPrimary {
+			return group, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// syncMemberRoles keeps the role label of each pod in sync with the group membership
+func (r *GroupReplicationClusterReconciler) syncMemberRoles(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, token string, log logr.Logger) ([]greatsqlv1.MemberStatus, error) {
+	pods, err := listMemberPods(ctx, r.Client, mgr.Namespace, mgr.Name)
+	if err != nil {
+		log.Error(err, "Could not list member pods")
+		return nil, err
+	}
+	if len(pods) == 0 {
+		// the cluster hibernates or has not been started yet
+		return nil, nil
+	}
+
+	group, err := r.getGroupMembers(pods, token)
+	if err != nil || group == nil {
+		// keep the current labels, the services keep routing until the group is reachable again
+		log.Info("Group membership is not available yet", "error", err)
+		return append([]greatsqlv1.MemberStatus(nil), mgr.Status.Members...), nil
+	}
+
+	templates := kube.NewVolumeClaimTemplates(mgr.Name, mgr.Spec.ClusterSpec.PodSpec, nil, kube.GroupVolumeOrdinal, true)
+	members := make([]greatsqlv1.MemberStatus, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		status := greatsqlv1.MemberStatus{Name: pod.Name}
+		status.Volumes = memberVolumes(ctx, r.Client, mgr.Namespace, pod.Name, templates)
+
+		var role string
+		if member, ok := group[pod.Name]; ok {
+			status.Host = member.Host
+			status.State = member.State
+			switch {
+			case member.IsPrimary():
+				role = consts.RolePrimary
+			case member.IsOnline():
+				role = consts.RoleSecondary
+			}
+		}
+		status.Role = role
+
+		if mgr.Spec.Paused {
+			// the labels are left as they are while the reconciliation is paused
+			status.ServingReads = role == consts.RoleSecondary && pod.Labels[consts.ServingReadsLabel] != "false"
+			members = append(members, status)
+			continue
+		}
+
+		if role == consts.RoleSecondary {
+			status.ServingReads = r.checkReplicaLag(mgr, pod, &status, token, log)
+		}
+
+		if err := setMemberLabels(ctx, r.Client, pod, role, status.ServingReads); err != nil {
+			log.Error(err, "Could not update member labels", "Pod", pod.Name)
+			return nil, err
+		}
+		members = append(members, status)
+	}
+
+	return members, nil
+}
+
+// lagThreshold returns the lag threshold of the replica lag policy
+func lagThreshold(policy *greatsqlv1.ReplicaLagPolicy) mysql.LagThreshold {
+	var threshold mysql.LagThreshold
+	if policy.MaxTransactionsInQueue != nil {
+		threshold.MaxTransactionsInQueue = *policy.MaxTransactionsInQueue
+	}
+	if policy.MaxSecondsBehind != nil {
+		threshold.MaxSecondsBehind = *policy.MaxSecondsBehind
+	}
+	if policy.RecoveryPercent != nil {
+		threshold.RecoveryPercent = int64(*policy.RecoveryPercent)
+	}
+	return threshold
+}
+
+// checkReplicaLag returns whether the secondary serves reads according to the replica lag policy,
+// an event is recorded each time the secondary leaves or rejoins the read-only service
+func (r *GroupReplicationClusterReconciler) checkReplicaLag(mgr *greatsqlv1.GroupReplicationCluster, pod *corev1.Pod, status *greatsqlv1.MemberStatus, token string, log logr.Logger) bool {
+	policy := mgr.Spec.ClusterSpec.ReplicaLag
+	if policy == nil {
+		return true
+	}
+
+	// a secondary without the label has just joined the read-only service
+	serving := pod.Labels[consts.ServingReadsLabel] != "false"
+	agentClient := newAgentClient(pod, token)
+	if agentClient == nil {
+		return serving
+	}
+	lag, err := agentClient.GetMemberLag()
+	if err != nil {
+		log.Info("Could not get member lag, keep the current routing", "Pod", pod.Name, "error", err)
+		return serving
+	}
+	status.TransactionsInQueue = lag.TransactionsInQueue
+	status.SecondsBehind = lag.SecondsBehind
+
+	next := lagThreshold(policy).ServeReads(serving, lag)
+	switch {
+	case serving && !next:
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeWarning, "ReplicaLagging",
+			"Member %s removed from the read-only service, %d transactions in queue, %d seconds behind",
+			pod.Name, lag.TransactionsInQueue, lag.SecondsBehind)
+	case !serving && next:
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "ReplicaCaughtUp",
+			"Member %s added back to the read-only service", pod.Name)
+	}
+	return next
+}
+
+// setMemberLabels sets the role and serving-reads labels of the pod, an empty role removes the labels
+func setMemberLabels(ctx context.Context, c client.Client, pod *corev1.Pod, role string, servingReads bool) error {
+	labels := map[string]string{}
+	if role != "" {
+		labels[consts.RoleLabel] = role
+	}
+	if role == consts.RoleSecondary {
+		labels[consts.ServingReadsLabel] = strconv.FormatBool(servingReads)
+	}
+
+	changed := false
+	for _, key := range []string{consts.RoleLabel, consts.ServingReadsLabel} {
+		current, ok := pod.Labels[key]
+		desired, want := labels[key]
+		if ok != want || current != desired {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	for _, key := range []string{consts.RoleLabel, consts.ServingReadsLabel} {
+		if value, ok := labels[key]; ok {
+			pod.Labels[key] = value
+		} else {
+			delete(pod.Labels, key)
+		}
+	}
+	if err := c.Patch(ctx, pod, patch); err != nil {
+		return err
+	}
+	logger.Info("Update member labels is successful", "Pod", pod.Name, "Role", role, "ServingReads", servingReads)
+	return nil
+}
+
+// createRoleServices applies the read-write and read-only services of the GroupReplicationCluster
+func (r *GroupReplicationClusterReconciler) createRoleServices(ctx context.Context, req ctrl.Request, mgr *greatsqlv1.GroupReplicationCluster, applier *kube.Applier, log logr.Logger) error {
+	primary := kube.NewRoleService(req.Name+consts.PrimaryServiceSuffix, req.Namespace, consts.GroupReplicationCluster, req.Name,
+		consts.RolePrimary, &mgr.ObjectMeta, kube.NewMySQLServicePorts(mgr.Spec.ClusterSpec.Ports), mgr.Spec.ClusterSpec.Type)
+	replicas := kube.NewRoleService(req.Name+consts.ReplicasServiceSuffix, req.Namespace, consts.GroupReplicationCluster, req.Name,
+		consts.RoleSecondary, &mgr.ObjectMeta, kube.NewMySQLServicePorts(mgr.Spec.ClusterSpec.Ports), mgr.Spec.ClusterSpec.Type)
+	replicas.Spec.Selector[consts.ServingReadsLabel] = "true"
+
+	for _, service := range []*corev1.Service{primary, replicas} {
+		if err := applier.Apply(ctx, mgr, service); err != nil {
+			log.Error(err, "Could not apply service", "Name", service.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+// getServiceAccessPoint returns the access point of the service, empty if the service does not exist yet
+func getServiceAccessPoint(ctx context.Context, c client.Client, name, namespace string) string {
+	svc := &corev1.Service{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, svc); err != nil {
+		return ""
+	}
+	return utils.GetServiceAccessPoint(*svc)
+}
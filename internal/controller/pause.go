@@ -0,0 +1,52 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+)
+
+// setPausedCondition sets the Paused condition of the status to the paused spec of the owner,
+// an event is recorded each time the reconciliation is paused or resumed
+func setPausedCondition(conditions *[]metav1.Condition, recorder record.EventRecorder, owner client.Object, paused bool) {
+	condition := metav1.Condition{
+		Type:               greatsqlv1.ConditionPaused,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Reconciling",
+		Message:            "The resources are reconciled",
+		ObservedGeneration: owner.GetGeneration(),
+	}
+	if paused {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Paused"
+		condition.Message = "The reconciliation is paused, only the status is updated"
+	}
+
+	switch wasPaused := meta.IsStatusConditionTrue(*conditions, greatsqlv1.ConditionPaused); {
+	case paused && !wasPaused:
+		recorder.Event(owner, corev1.EventTypeNormal, "Paused", "Reconciliation is paused, the resources are left as they are")
+	case !paused && wasPaused:
+		recorder.Event(owner, corev1.EventTypeNormal, "Resumed", "Reconciliation is resumed")
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/go-logr/logr"
+)
+
+// deletePodDisruptionBudget deletes the PodDisruptionBudget if it exists
+func deletePodDisruptionBudget(ctx context.Context, c client.Client, key client.ObjectKey, log logr.Logger) error {
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := c.Get(ctx, key, pdb); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if err := c.Delete(ctx, pdb); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "Could not delete podDisruptionBudget", "Name", key.Name)
+		return err
+	}
+	log.Info("Delete podDisruptionBudget is successful", "Name", key.Name, "Namespace", key.Namespace)
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/utils"
+	"github.com/go-logr/logr"
+)
+
+// syncPrimaryPlacement switches the primary over to an ONLINE secondary running on a node selected by the
+// primary node selector. The primary is kept if it already runs on such a node or if no secondary does,
+// nothing is switched while the group is converted from a SingleInstance
+func (r *GroupReplicationClusterReconciler) syncPrimaryPlacement(ctx context.Context, mgr *greatsqlv1.GroupReplicationCluster, members []greatsqlv1.MemberStatus, token string, log logr.Logger) error {
+	if mgr.Spec.ClusterSpec.PodSpec == nil || mgr.Spec.ClusterSpec.PodSpec.Affinity == nil {
+		return nil
+	}
+	selector := mgr.Spec.ClusterSpec.PodSpec.Affinity.PrimaryNodeSelector
+	if selector == nil {
+		return nil
+	}
+	if conversion := mgr.Status.Conversion; conversion != nil && conversion.Phase != greatsqlv1.ConversionCompleted && conversion.Phase != greatsqlv1.ConversionFailed {
+		return nil
+	}
+
+	pods, err := listMemberPods(ctx, r.Client, mgr.Namespace, mgr.Name)
+	if err != nil {
+		log.Error(err, "Could not list member pods")
+		return err
+	}
+	var primary *corev1.Pod
+	var secondaries []*corev1.Pod
+	for _, member := range members {
+		pod := podByName(pods, member.Name)
+		if pod == nil || pod.Spec.NodeName == "" {
+			continue
+		}
+		switch member.Role {
+		case consts.RolePrimary:
+			primary = pod
+		case consts.RoleSecondary:
+			secondaries = append(secondaries, pod)
+		}
+	}
+	if primary == nil {
+		return nil
+	}
+
+	matches := func(pod *corev1.Pod) (bool, error) {
+		node := &corev1.Node{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+			log.Error(err, "Unable to fetch node", "Node", pod.Spec.NodeName)
+			return false, client.IgnoreNotFound(err)
+		}
+		return utils.MatchNodeSelector(selector, node), nil
+	}
+	if ok, err := matches(primary); err != nil || ok {
+		return err
+	}
+
+	for _, pod := range secondaries {
+		ok, err := matches(pod)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		agentClient := newAgentClient(primary, token)
+		if agentClient == nil {
+			return nil
+		}
+		group, err := r.getGroupMembers(pods, token)
+		if err != nil || group == nil {
+			log.Info("Group membership is not available yet", "error", err)
+			return nil
+		}
+		target, ok := group[pod.Name]
+		if !ok || !target.IsOnline() {
+			continue
+		}
+		if _, err := agentClient.SetPrimary(target.ID); err != nil {
+			log.Error(err, "Could not switch the primary over", "From", primary.Name, "To", pod.Name)
+			r.EventRecorder.Eventf(mgr, corev1.EventTypeWarning, "PrimarySwitchoverFailed", "Could not switch the primary over from %s to %s: %v", primary.Name, pod.Name, err)
+			return nil
+		}
+		log.Info("Switch primary over is successful", "From", primary.Name, "To", pod.Name, "Node", pod.Spec.NodeName)
+		r.EventRecorder.Eventf(mgr, corev1.EventTypeNormal, "PrimarySwitchedOver",
+			"Primary is switched over from %s to %s running on node %s selected by the primary node selector", primary.Name, pod.Name, pod.Spec.NodeName)
+		return nil
+	}
+	return nil
+}
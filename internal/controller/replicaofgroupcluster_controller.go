@@ -0,0 +1,224 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	goerrors "errors"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+	"github.com/gagraler/greatsql-operator/internal/utils"
+	"github.com/go-logr/logr"
+)
+
+// ReplicaofGroupClusterReconciler reconciles a ReplicaofGroupCluster object
+type ReplicaofGroupClusterReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	Log           logr.Logger
+	EventRecorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=replicaofgroupclusters,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=replicaofgroupclusters/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=replicaofgroupclusters/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create
+
+// Reconcile applies the source and the replicas of the ReplicaofGroupCluster,
+// keeps the replicas replicating from the current source and promotes a replica when the source fails.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
+func (r *ReplicaofGroupClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
+	log := logger.WithValues("ReplicaofGroupCluster", req.NamespacedName)
+	log.Info("Reconciling ReplicaofGroupCluster...")
+
+	roc := &greatsqlv1.ReplicaofGroupCluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, roc); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("ReplicaofGroupCluster resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch ReplicaofGroupCluster")
+		return ctrl.Result{}, err
+	}
+
+	finalizer := &utils.GreatSqlFinalizer{
+		Cli:      r.Client,
+		GreatSql: roc,
+		Kind:     consts.ReplicaofGroupCluster,
+	}
+	if roc.DeletionTimestamp != nil {
+		return r.handleFinalizer(finalizer, log)
+	}
+	if err := finalizer.AddFinalizer(); err != nil {
+		log.Error(err, "Could not add finalizer")
+		return ctrl.Result{}, err
+	}
+
+	token, err := ensureAgentSecret(ctx, r.Client, roc, consts.ReplicaofGroupCluster, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	applier := kube.NewApplier(r.Client, r.Scheme, r.EventRecorder)
+	if err := r.applyResources(ctx, req, roc, applier, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := roc.Status.DeepCopy()
+	members, err := r.syncReplication(ctx, roc, status, token, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := autoGrowStorage(ctx, r.Client, r.EventRecorder, roc, &roc.Spec.PodSpec, token, func(podName, volume string) string {
+		return statefulSetClaimName(kube.StorageVolumeName(roc.Name, volume, 0, false), podName)
+	}, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateStatus(ctx, roc, status, members, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: memberSyncInterval}, nil
+}
+
+// handleFinalizer cleans up the deleted ReplicaofGroupCluster,
+// the deletion is requeued while the members are stopping or the final snapshots are in progress
+func (r *ReplicaofGroupClusterReconciler) handleFinalizer(finalizer *utils.GreatSqlFinalizer, log logr.Logger) (ctrl.Result, error) {
+	if err := finalizer.HandleFinalizer(); err != nil {
+		if goerrors.Is(err, utils.ErrCleanupInProgress) {
+			log.Info("Waiting for the cleanup to finish", "reason", err.Error())
+			return ctrl.Result{RequeueAfter: cleanupInterval}, nil
+		}
+		log.Error(err, "Could not handle finalizer")
+		r.EventRecorder.Event(finalizer.GreatSql, corev1.EventTypeWarning, "FinalizeFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// applyResources applies the configMap, the statefulSet and the services of the ReplicaofGroupCluster,
+// the statefulSet is left as is while the claims of the members are expanded
+func (r *ReplicaofGroupClusterReconciler) applyResources(ctx context.Context, req ctrl.Request, roc *greatsqlv1.ReplicaofGroupCluster, applier *kube.Applier, log logr.Logger) error {
+	cnf := new(mysql.MySQLConfig)
+	cnf.ServerID = "1"
+	cnf.EnableSemiSync = roc.Spec.Replication.Mode == greatsqlv1.ReplicationModeSemiSync
+	cnf.ReportPort = int(consts.MysqlPort)
+	cnf.InnodbBufferPoolSize = "1G"
+	cnf.StorageLayout = kube.StorageLayout(&roc.Spec.PodSpec)
+	if len(roc.Spec.PodSpec.Containers) > 0 {
+		if memoryReq := roc.Spec.PodSpec.Containers[0].Resources.Requests.Memory().Value(); memoryReq > 0 {
+			cnf.InnodbBufferPoolSize = mysql.CalculateInnodbBufferPoolSize(memoryReq)
+		}
+	}
+	data, err := cnf.String(*cnf)
+	if err != nil {
+		log.Error(err, "Could not get configMap data")
+		return err
+	}
+	configMap := kube.NewConfigMap(req.Name+"-"+consts.Config, req.Namespace, consts.ConfigFile, data)
+
+	headless := kube.NewService(req.Name, req.Namespace, consts.ReplicaofGroupCluster, &roc.ObjectMeta, kube.NewMySQLServicePorts(roc.Spec.Ports), corev1.ServiceTypeClusterIP)
+	headless.Name = req.Name + consts.HeadlessServiceSuffix
+	headless.Spec.ClusterIP = corev1.ClusterIPNone
+	// the replicas resolve the source before it is ready
+	headless.Spec.PublishNotReadyAddresses = true
+
+	primary := kube.NewRoleService(req.Name+consts.PrimaryServiceSuffix, req.Namespace, consts.ReplicaofGroupCluster, req.Name,
+		consts.RolePrimary, &roc.ObjectMeta, kube.NewMySQLServicePorts(roc.Spec.Ports), roc.Spec.Type)
+	replicas := kube.NewRoleService(req.Name+consts.ReplicasServiceSuffix, req.Namespace, consts.ReplicaofGroupCluster, req.Name,
+		consts.RoleSecondary, &roc.ObjectMeta, kube.NewMySQLServicePorts(roc.Spec.Ports), roc.Spec.Type)
+	replicas.Spec.Selector[consts.ServingReadsLabel] = "true"
+
+	objs := []client.Object{configMap, headless, primary, replicas}
+	sts := kube.NewReplicaStatefulSet(configMap.Name, roc)
+	recreating, err := expandStatefulSetVolumes(ctx, r.Client, r.EventRecorder, roc, sts, log)
+	if err != nil {
+		return err
+	}
+	if !recreating {
+		objs = append(objs, sts)
+	}
+	for _, obj := range objs {
+		if err := applier.Apply(ctx, roc, obj); err != nil {
+			log.Error(err, "Could not apply resource", "Kind", obj.GetObjectKind().GroupVersionKind().Kind, "Name", obj.GetName())
+			return err
+		}
+	}
+	return nil
+}
+
+// updateStatus updates the status of the ReplicaofGroupCluster
+func (r *ReplicaofGroupClusterReconciler) updateStatus(ctx context.Context, roc *greatsqlv1.ReplicaofGroupCluster, status *greatsqlv1.ReplicaofGroupClusterStatus, members []greatsqlv1.MemberStatus, log logr.Logger) error {
+	status.PrimaryAccessPoint = getServiceAccessPoint(ctx, r.Client, roc.Name+consts.PrimaryServiceSuffix, roc.Namespace)
+	status.ReplicasAccessPoint = getServiceAccessPoint(ctx, r.Client, roc.Name+consts.ReplicasServiceSuffix, roc.Namespace)
+	status.AccessPoint = status.PrimaryAccessPoint
+	status.Members = members
+	status.Size = roc.Spec.GetSize()
+
+	var ready int32
+	for _, member := range members {
+		if member.Role == consts.RolePrimary || (member.Role == consts.RoleSecondary && member.State == "Running") {
+			ready++
+		}
+	}
+	status.Ready = ready
+
+	if reflect.DeepEqual(roc.Status, *status) {
+		return nil
+	}
+
+	roc.Status = *status
+	if err := r.Client.Status().Update(ctx, roc); err != nil {
+		log.Error(err, "Could not update status")
+		return err
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ReplicaofGroupClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&greatsqlv1.ReplicaofGroupCluster{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Complete(r)
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/agent"
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+	"github.com/go-logr/logr"
+)
+
+// defaultFailoverDelay is how long the source has to be unavailable before a replica is promoted
+const defaultFailoverDelay = 30 * time.Second
+
+// podOrdinal returns the ordinal of the statefulset pod
+func podOrdinal(name string) int {
+	ordinal, err := strconv.Atoi(name[strings.LastIndex(name, "-")+1:])
+	if err != nil {
+		return 0
+	}
+	return ordinal
+}
+
+// memberServerID returns the server_id of the member, unique in the cluster
+func memberServerID(podName string) int {
+	return podOrdinal(podName) + 1
+}
+
+// failoverDelay returns the failover delay of the replication spec
+func failoverDelay(spec greatsqlv1.ReplicationSpec) time.Duration {
+	if spec.FailoverDelaySeconds == nil {
+		return defaultFailoverDelay
+	}
+	return time.Duration(*spec.FailoverDelaySeconds) * time.Second
+}
+
+// syncReplication keeps the source writable and the replicas replicating from it, promotes the most advanced
+// replica once the source has been unavailable for the failover delay, and labels the pods with their role
+func (r *ReplicaofGroupClusterReconciler) syncReplication(ctx context.Context, roc *greatsqlv1.ReplicaofGroupCluster, status *greatsqlv1.ReplicaofGroupClusterStatus, token string, log logr.Logger) ([]greatsqlv1.MemberStatus, error) {
+	pods, err := listMemberPods(ctx, r.Client, roc.Namespace, roc.Name)
+	if err != nil {
+		log.Error(err, "Could not list member pods")
+		return nil, err
+	}
+
+	states := make(map[string]mysql.ReplicaStatus, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		agentClient := newAgentClient(pod, token)
+		if agentClient == nil {
+			continue
+		}
+		state, err := agentClient.GetReplicaStatus()
+		if err != nil {
+			log.Info("Could not get replication status", "Pod", pod.Name, "error", err)
+			continue
+		}
+		states[pod.Name] = state
+	}
+
+	semiSync := roc.Spec.Replication.Mode == greatsqlv1.ReplicationModeSemiSync
+	source := status.Source
+	if source == "" {
+		// bootstrap, the first member becomes the source once it is reachable
+		source = fmt.Sprintf("%s-0", roc.Name)
+	}
+	sourceHost := kube.GetPodFQDN(source, roc.Name+consts.HeadlessServiceSuffix, roc.Namespace)
+
+	if _, ok := states[source]; ok || !isPodDown(podByName(pods, source)) {
+		// an agent which does not answer while the pod is ready, e.g. during its restart, does not make the source unavailable
+		status.SourceUnavailableSince = nil
+	} else if status.Source != "" {
+		promoted, err := r.failover(ctx, roc, status, states, sourceHost, semiSync, log)
+		if err != nil {
+			return nil, err
+		}
+		if promoted != "" {
+			source = promoted
+			sourceHost = kube.GetPodFQDN(source, roc.Name+consts.HeadlessServiceSuffix, roc.Namespace)
+		}
+	}
+
+	if state, ok := states[source]; ok {
+		if status.Source == "" || state.Replica || state.ReadOnly || state.ServerID != memberServerID(source) {
+			state, err = r.promote(roc, podByName(pods, source), token, semiSync, log)
+			if err != nil {
+				return nil, err
+			}
+			states[source] = state
+		}
+		status.Source = source
+	}
+
+	templates := kube.NewVolumeClaimTemplates(roc.Name, &roc.Spec.PodSpec, nil, 0, false)
+	members := make([]greatsqlv1.MemberStatus, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		member := greatsqlv1.MemberStatus{
+			Name:    pod.Name,
+			Host:    kube.GetPodFQDN(pod.Name, roc.Name+consts.HeadlessServiceSuffix, roc.Namespace),
+			Volumes: memberVolumes(ctx, r.Client, roc.Namespace, pod.Name, templates),
+		}
+
+		state, ok := states[pod.Name]
+		switch {
+		case !ok:
+			member.State = "Unavailable"
+		case pod.Name == source:
+			member.Role = consts.RolePrimary
+			member.State = state.State()
+		case status.Source != "":
+			sourceState, sourceOK := states[source]
+			var fenced bool
+			state, fenced = r.syncReplica(roc, pod, state, sourceState, sourceOK, sourceHost, semiSync, token, log)
+			member.Role = consts.RoleSecondary
+			member.State = state.State()
+			member.SecondsBehind = state.SecondsBehind
+			member.LastError = state.LastError
+			member.ServingReads = r.checkReplicaLag(roc, pod, state, log)
+			if fenced {
+				member.State = MemberStateFenced
+				member.ServingReads = false
+			}
+		}
+
+		if err := setMemberLabels(ctx, r.Client, pod, member.Role, member.ServingReads); err != nil {
+			log.Error(err, "Could not update member labels", "Pod", pod.Name)
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// failover promotes the most advanced replica once the source has been unavailable for the failover delay,
+// the new source is recorded in the status before the replicas are pointed to it, returns the promoted member
+func (r *ReplicaofGroupClusterReconciler) failover(ctx context.Context, roc *greatsqlv1.ReplicaofGroupCluster, status *greatsqlv1.ReplicaofGroupClusterStatus,
+	states map[string]mysql.ReplicaStatus, sourceHost string, semiSync bool, log logr.Logger) (string, error) {
+	if status.SourceUnavailableSince == nil {
+		now := metav1.Now()
+		status.SourceUnavailableSince = &now
+		r.EventRecorder.Eventf(roc, corev1.EventTypeWarning, "SourceUnavailable", "Source %s is unavailable", status.Source)
+		return "", nil
+	}
+	if roc.Spec.Replication.AutoFailover != nil && !*roc.Spec.Replication.AutoFailover {
+		return "", nil
+	}
+	if time.Since(status.SourceUnavailableSince.Time) < failoverDelay(roc.Spec.Replication) {
+		return "", nil
+	}
+
+	candidate := mysql.PromotionCandidate(states, sourceHost, consts.MysqlPort)
+	if candidate == "" {
+		log.Info("No replica can be promoted", "Source", status.Source)
+		return "", nil
+	}
+	log.Info("Source is unavailable, promoting a replica", "Source", status.Source, "Candidate", candidate)
+
+	roc.Status.Source = candidate
+	roc.Status.SourceUnavailableSince = nil
+	if err := r.Client.Status().Update(ctx, roc); err != nil {
+		log.Error(err, "Could not record the new source", "Candidate", candidate)
+		return "", err
+	}
+	r.EventRecorder.Eventf(roc, corev1.EventTypeWarning, "SourceFailover",
+		"Source %s has been unavailable since %s, promoting replica %s", status.Source, status.SourceUnavailableSince.Format(time.RFC3339), candidate)
+
+	status.Source = candidate
+	status.SourceUnavailableSince = nil
+	return candidate, nil
+}
+
+// isPodDown returns true if the pod is gone, being deleted or not ready
+func isPodDown(pod *corev1.Pod) bool {
+	return pod == nil || pod.DeletionTimestamp != nil || !isPodReady(pod)
+}
+
+// podByName returns the pod with the given name
+func podByName(pods []corev1.Pod, name string) *corev1.Pod {
+	for i := range pods {
+		if pods[i].Name == name {
+			return &pods[i]
+		}
+	}
+	return nil
+}
+
+// promote makes the member the writable source of the cluster
+func (r *ReplicaofGroupClusterReconciler) promote(roc *greatsqlv1.ReplicaofGroupCluster, pod *corev1.Pod, token string, semiSync bool, log logr.Logger) (mysql.ReplicaStatus, error) {
+	source := pod.Name
+	agentClient := newAgentClient(pod, token)
+	if agentClient == nil {
+		return mysql.ReplicaStatus{}, fmt.Errorf("source %s has no ip", source)
+	}
+
+	state, err := agentClient.Promote(mysql.PromoteRequest{ServerID: memberServerID(source), SemiSync: semiSync})
+	if err != nil {
+		log.Error(err, "Could not promote the source", "Pod", source)
+		r.EventRecorder.Eventf(roc, corev1.EventTypeWarning, "PromoteFailed", "Could not promote %s: %v", source, err)
+		return state, err
+	}
+	log.Info("Promote source is successful", "Pod", source)
+	r.EventRecorder.Eventf(roc, corev1.EventTypeNormal, "Promoted", "Member %s is the source", source)
+	return state, nil
+}
+
+// MemberStateFenced is the state of a member which has executed transactions the source does not have,
+// it is kept read-only and is not pointed to the source until it is resynced by hand
+const MemberStateFenced = "Fenced"
+
+// syncReplica points the replica to the source when it replicates from another member, is writable,
+// or its replication threads have stopped, and returns its replication state. A member which has executed
+// transactions the source does not have, e.g. a former source which comes back after a failover, is fenced:
+// it is made read-only and is not pointed to the source. It returns true if the member is fenced
+func (r *ReplicaofGroupClusterReconciler) syncReplica(roc *greatsqlv1.ReplicaofGroupCluster, pod *corev1.Pod, state, sourceState mysql.ReplicaStatus,
+	sourceOK bool, sourceHost string, semiSync bool, token string, log logr.Logger) (mysql.ReplicaStatus, bool) {
+	serverID := memberServerID(pod.Name)
+	replicaOfSource := state.IsReplicaOf(sourceHost, consts.MysqlPort)
+	if replicaOfSource && state.ReadOnly && state.ServerID == serverID &&
+		(state.IsRunning() || state.IORunning == "Connecting") {
+		return state, false
+	}
+
+	agentClient := newAgentClient(pod, token)
+	if agentClient == nil {
+		return state, false
+	}
+	if !replicaOfSource {
+		// the transactions of the member are only known to be part of the source once it replicates from it
+		if !sourceOK {
+			return state, false
+		}
+		errant, err := mysql.GTIDSubtract(state.GTIDExecuted, sourceState.GTIDExecuted)
+		if err != nil {
+			log.Error(err, "Could not compare the transactions of the member with the source", "Pod", pod.Name)
+			state.LastError = err.Error()
+			return state, true
+		}
+		if errant != "" {
+			return r.fenceMember(roc, pod, agentClient, state, errant, log), true
+		}
+	}
+
+	next, err := agentClient.StartReplica(mysql.ReplicationSource{
+		Host:     sourceHost,
+		Port:     consts.MysqlPort,
+		ServerID: serverID,
+		SemiSync: semiSync,
+	})
+	if err != nil {
+		log.Error(err, "Could not start replica", "Pod", pod.Name)
+		r.EventRecorder.Eventf(roc, corev1.EventTypeWarning, "ReplicaFailed", "Could not point %s to the source: %v", pod.Name, err)
+		return state, false
+	}
+	if !state.Replica || !replicaOfSource {
+		r.EventRecorder.Eventf(roc, corev1.EventTypeNormal, "ReplicaConfigured", "Member %s replicates from %s", pod.Name, sourceHost)
+	}
+	log.Info("Start replica is successful", "Pod", pod.Name, "Source", sourceHost)
+	return next, false
+}
+
+// fenceMember makes the member which has executed errant transactions read-only, the event is recorded once
+// when the member is fenced
+func (r *ReplicaofGroupClusterReconciler) fenceMember(roc *greatsqlv1.ReplicaofGroupCluster, pod *corev1.Pod, agentClient *agent.Client,
+	state mysql.ReplicaStatus, errant string, log logr.Logger) mysql.ReplicaStatus {
+	state.LastError = fmt.Sprintf("errant transactions %s are not part of the source", errant)
+	if state.ReadOnly {
+		return state
+	}
+	next, err := agentClient.SetReadOnly()
+	if err != nil {
+		log.Error(err, "Could not fence the member", "Pod", pod.Name)
+		return state
+	}
+	next.LastError = state.LastError
+	log.Info("Member has errant transactions, it is fenced", "Pod", pod.Name, "ErrantTransactions", errant)
+	r.EventRecorder.Eventf(roc, corev1.EventTypeWarning, "ErrantTransactions",
+		"Member %s has executed transactions the source does not have: %s, it is read-only and not resynced", pod.Name, errant)
+	return next
+}
+
+// checkReplicaLag returns whether the replica serves reads, a replica whose replication threads
+// are not running never does, the lag threshold of the replica lag policy applies to the others
+func (r *ReplicaofGroupClusterReconciler) checkReplicaLag(roc *greatsqlv1.ReplicaofGroupCluster, pod *corev1.Pod, state mysql.ReplicaStatus, log logr.Logger) bool {
+	if !state.IsRunning() {
+		return false
+	}
+	policy := roc.Spec.ReplicaLag
+	if policy == nil {
+		return true
+	}
+
+	serving := pod.Labels[consts.ServingReadsLabel] != "false"
+	next := lagThreshold(policy).ServeReads(serving, mysql.MemberLag{SecondsBehind: state.SecondsBehind})
+	switch {
+	case serving && !next:
+		r.EventRecorder.Eventf(roc, corev1.EventTypeWarning, "ReplicaLagging",
+			"Replica %s removed from the read-only service, %d seconds behind", pod.Name, state.SecondsBehind)
+	case !serving && next:
+		r.EventRecorder.Eventf(roc, corev1.EventTypeNormal, "ReplicaCaughtUp",
+			"Replica %s added back to the read-only service", pod.Name)
+	}
+	return next
+}
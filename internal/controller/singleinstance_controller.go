@@ -18,26 +18,26 @@ package controller
 
 import (
 	"context"
-	"fmt"
-	"reflect"
+	goerrors "errors"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/client-go/tools/record"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/bytedance/sonic"
 	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
 	"github.com/gagraler/greatsql-operator/internal/consts"
 	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
 	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
 	"github.com/gagraler/greatsql-operator/internal/utils"
 	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/types"
 )
 
 // SingleInstanceReconciler reconciles a SingleInstance object
@@ -55,9 +55,16 @@ var (
 //+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=singleinstances,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=singleinstances/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=greatsql.greatsql.cn,resources=singleinstances/finalizers,verbs=update
-//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;delete
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -75,13 +82,30 @@ func (r *SingleInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	SingleInstance := &greatsqlv1.SingleInstance{}
 	if err := r.getSingleInstance(ctx, req, SingleInstance, r.Log); err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if err := r.handleFinalizer(ctx, SingleInstance, r.Log); err != nil {
-		return ctrl.Result{}, err
+	if SingleInstance.DeletionTimestamp != nil {
+		return r.handleFinalizer(ctx, SingleInstance, r.Log)
+	}
+
+	if SingleInstance.Spec.Paused {
+		return r.reconcilePaused(ctx, req, SingleInstance, r.Log)
+	}
+
+	// the status is updated after every reconciliation, the error is reported in it
+	result, err := r.reconcileResources(ctx, req, SingleInstance)
+	if statusErr := r.updateStatus(ctx, SingleInstance, err); statusErr != nil && err == nil {
+		return ctrl.Result{}, statusErr
 	}
+	if result.IsZero() && meta.IsStatusConditionTrue(SingleInstance.Status.Conditions, greatsqlv1.ConditionDegraded) {
+		result.RequeueAfter = memberSyncInterval
+	}
+	return result, err
+}
 
+// reconcileResources validates the SingleInstance, then creates and applies its resources
+func (r *SingleInstanceReconciler) reconcileResources(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance) (ctrl.Result, error) {
 	if err := r.validateAndAddFinalizer(SingleInstance, req, r.Log); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -99,36 +123,42 @@ func (r *SingleInstanceReconciler) getSingleInstance(ctx context.Context, req ct
 	if err != nil {
 		if errors.IsNotFound(err) {
 			r.Log.Info("SingleGreateSql resource not found. Ignoring since object must be deleted")
-			return nil
+			return err
 		}
 		r.Log.Error(err, "unable to fetch SingleGreateSql")
-		return client.IgnoreNotFound(err)
+		return err
 	}
 	return nil
 }
 
-// handleFinalizer handles the finalizer of the SingleInstance
-func (r *SingleInstanceReconciler) handleFinalizer(ctx context.Context, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
+// reconcilePaused only updates the status of the paused SingleInstance, the resources are left as they are.
+// The deletion of a paused instance is still handled
+func (r *SingleInstanceReconciler) reconcilePaused(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) (ctrl.Result, error) {
+	r.Log.Info("Reconciliation is paused, only the status is updated")
+	if err := r.updateStatus(ctx, SingleInstance, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: memberSyncInterval}, nil
+}
+
+// handleFinalizer handles the finalizer of the SingleInstance,
+// the deletion is requeued while the members are stopping or the final snapshot is in progress
+func (r *SingleInstanceReconciler) handleFinalizer(ctx context.Context, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) (ctrl.Result, error) {
 	finalizer := &utils.GreatSqlFinalizer{
 		Cli:      r.Client,
 		GreatSql: SingleInstance,
+		Kind:     consts.SingleInstance,
 	}
-	if SingleInstance.DeletionTimestamp != nil {
-		if err := finalizer.HandleFinalizer(); err != nil {
-			r.Log.Error(err, "Could not handle finalizer")
-			return err
-		}
-		if err := finalizer.RemoveFinalizer(); err != nil {
-			r.Log.Error(err, "Could not remove finalizer")
-			return err
-		}
-		if err := r.Client.Update(ctx, SingleInstance); err != nil {
-			r.Log.Error(err, "Could not update GreatSql")
-			return err
+	if err := finalizer.HandleFinalizer(); err != nil {
+		if goerrors.Is(err, utils.ErrCleanupInProgress) {
+			r.Log.Info("Waiting for the cleanup to finish", "reason", err.Error())
+			return ctrl.Result{RequeueAfter: cleanupInterval}, nil
 		}
-		return nil
+		r.Log.Error(err, "Could not handle finalizer")
+		r.EventRecorder.Event(SingleInstance, corev1.EventTypeWarning, "FinalizeFailed", err.Error())
+		return ctrl.Result{}, err
 	}
-	return nil
+	return ctrl.Result{}, nil
 }
 
 // validateAndAddFinalizer validates the spec of the SingleInstance and adds the finalizer
@@ -140,6 +170,7 @@ func (r *SingleInstanceReconciler) validateAndAddFinalizer(SingleInstance *great
 	finalizer := &utils.GreatSqlFinalizer{
 		Cli:      r.Client,
 		GreatSql: SingleInstance,
+		Kind:     consts.SingleInstance,
 	}
 	if err := finalizer.AddFinalizer(); err != nil {
 		r.Log.Error(err, "Could not add finalizer")
@@ -148,84 +179,51 @@ func (r *SingleInstanceReconciler) validateAndAddFinalizer(SingleInstance *great
 	return nil
 }
 
-// createResources creates the resources
+// createResources creates the resources which are only created once, the others are applied by watchResource
 func (r *SingleInstanceReconciler) createResources(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
-	deployGreatsql := &appsv1.Deployment{}
-	if err := r.Client.Get(ctx, req.NamespacedName, deployGreatsql); err != nil {
-		if err := r.createConfigMap(ctx, req, log); err != nil {
-			return err
-		}
+	if _, err := ensureAgentSecret(ctx, r.Client, SingleInstance, consts.SingleInstance, log); err != nil {
+		return err
+	}
+
+	sts := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, req.NamespacedName, sts); err != nil {
 		if err := r.createPersistentVolumeClaim(ctx, req, SingleInstance, log); err != nil {
 			return err
 		}
-		if err := r.createDeployment(ctx, req, SingleInstance, log); err != nil {
-			return err
-		}
-		if err := r.createService(ctx, req, SingleInstance, log); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// createConfigMap creates a ConfigMap for the SingleInstance
-func (r *SingleInstanceReconciler) createConfigMap(ctx context.Context, req ctrl.Request, log logr.Logger) error {
-	cnf := &mysql.MySQLConfig{
-		ServerID:                   "0",
-		EnableCluster:              false,
-		GroupReplicationGroupName:  "greatsql",
-		GroupReplicationGroupSeeds: "",
-		ReportHost:                 "",
-		ReportPort:                 3306,
-		InnodbBufferPoolSize:       "1G",
-	}
-	data, err := cnf.String(*cnf)
-	if err != nil {
-		r.Log.Error(err, "Could not get configMap data")
-		return err
 	}
-	configMap := kube.NewConfigMap(req.Name+"-config", req.Namespace, "my.cnf", data)
-	if err := r.Client.Create(ctx, configMap); err != nil {
-		r.Log.Error(err, "Could not create configMap")
-		return err
-	}
-	r.Log.Info("Create configMap is successful", "Name", configMap.Name, "Namespace", configMap.Namespace)
 	return nil
 }
 
-// createPersistentVolumeClaim creates a PersistentVolumeClaim for the SingleInstance
+// createPersistentVolumeClaim creates the PersistentVolumeClaims of the storage layout of the SingleInstance
 func (r *SingleInstanceReconciler) createPersistentVolumeClaim(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
-	pvc := kube.NewPersistentVolumeClaim(req.Name, req.Namespace, &SingleInstance.Spec.PodSpec)
-	if err := r.Client.Create(ctx, pvc); err != nil {
-		r.Log.Error(err, "Could not create persistentVolumeClaim")
-		return err
-	}
-	r.Log.Info("Create persistentVolumeClaim is successful", "Name", pvc.Name, "Namespace", pvc.Namespace)
-	return nil
-}
-
-// createDeployment creates a Deployment for the SingleInstance
-func (r *SingleInstanceReconciler) createDeployment(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
-	deploy := kube.NewDeployment(req.Name+consts.Config, SingleInstance, int(*SingleInstance.Spec.Size))
-	if err := r.Client.Create(ctx, deploy); err != nil {
-		r.Log.Error(err, "Could not create deployment")
-		return err
+	for _, pvc := range kube.NewPersistentVolumeClaims(req.Name, req.Namespace, &SingleInstance.Spec.PodSpec) {
+		if err := r.Client.Create(ctx, pvc); err != nil {
+			if errors.IsAlreadyExists(err) {
+				continue
+			}
+			r.Log.Error(err, "Could not create persistentVolumeClaim", "Name", pvc.Name)
+			return err
+		}
+		r.Log.Info("Create persistentVolumeClaim is successful", "Name", pvc.Name, "Namespace", pvc.Namespace)
 	}
-	r.Log.Info("Create deployment is successful", "Name", deploy.Name, "Namespace", deploy.Namespace)
 	return nil
 }
 
-// createService creates a Service for the SingleInstance
-func (r *SingleInstanceReconciler) createService(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
-	service := kube.NewService(req.Name, req.Namespace, consts.SingleInstance, &SingleInstance.ObjectMeta, SingleInstance.Spec.Ports, SingleInstance.Spec.Type)
-	if err := r.Client.Create(ctx, service); err != nil {
-		r.Log.Error(err, "Could not create service")
-		return err
-	}
-	log.Info("Create service is successful", "Name", service.Name, "Namespace", service.Namespace)
-	if err := r.updateStatus(ctx, SingleInstance, *service); err != nil {
-		r.Log.Error(err, "Could not update status")
-		return err
+// expandPersistentVolumeClaims expands the PersistentVolumeClaims of the SingleInstance when the storage size is increased
+func (r *SingleInstanceReconciler) expandPersistentVolumeClaims(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
+	for _, desired := range kube.NewPersistentVolumeClaims(req.Name, req.Namespace, &SingleInstance.Spec.PodSpec) {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(desired), pvc); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			log.Error(err, "Unable to fetch persistentVolumeClaim", "Name", desired.Name)
+			return err
+		}
+		if _, err := expandPersistentVolumeClaim(ctx, r.Client, r.EventRecorder, SingleInstance, pvc,
+			*desired.Spec.Resources.Requests.Storage(), log); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -253,103 +251,149 @@ func (r *SingleInstanceReconciler) validateSpec(spec greatsqlv1.SingleInstanceSp
 	return nil
 }
 
-// watchResource watches the resource
+// watchResource applies the desired state of the owned resources, out-of-band edits to them are reverted
 func (r *SingleInstanceReconciler) watchResource(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance) (ctrl.Result, error) {
 	log := logger.WithValues("Request.Service.Namespace", req.Namespace, "Request.Service.Name", req.Name)
+	applier := kube.NewApplier(r.Client, r.Scheme, r.EventRecorder)
 
-	// Update spec annotation
-	if err := r.updateSpecAnnotation(ctx, SingleInstance, log); err != nil {
+	// Update ConfigMap
+	if err := r.updateConfigMap(ctx, req, SingleInstance, applier, log); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Update Deployment
-	if err := r.updateDeployment(ctx, req, SingleInstance, log); err != nil {
+	// Expand PersistentVolumeClaims
+	if err := r.expandPersistentVolumeClaims(ctx, req, SingleInstance, log); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	// Update Service
-	if err := r.updateService(ctx, req, SingleInstance, log); err != nil {
+	if err := r.updateService(ctx, req, SingleInstance, applier, log); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Update ConfigMap
-	if err := r.updateConfigMap(ctx, req, log); err != nil {
+	// Migrate the pod of a Deployment based instance, the statefulset starts once it is stopped
+	migrating, err := r.migrateDeployment(ctx, req, SingleInstance, log)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if migrating {
+		return ctrl.Result{RequeueAfter: cleanupInterval}, nil
+	}
 
-	return ctrl.Result{}, nil
-}
+	// Update StatefulSet
+	if err := r.updateStatefulSet(ctx, req, SingleInstance, applier, log); err != nil {
+		return ctrl.Result{}, err
+	}
 
-// updateSpecAnnotation updates the spec annotation
-func (r *SingleInstanceReconciler) updateSpecAnnotation(ctx context.Context, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
-	data, err := sonic.Marshal(SingleInstance.Spec)
-	if err != nil {
-		r.Log.Error(err, "Could not marshal spec")
-		return err
+	// Update PodDisruptionBudget
+	if err := r.updatePodDisruptionBudget(ctx, req, SingleInstance, applier, log); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	if SingleInstance.Annotations == nil {
-		SingleInstance.Annotations = make(map[string]string)
+	// Apply the init sql scripts once the instance is ready
+	if err := r.syncInitSQL(ctx, SingleInstance, log); err != nil {
+		return ctrl.Result{}, err
 	}
-	SingleInstance.Annotations["spec"] = string(data)
 
-	if err := r.Client.Update(ctx, SingleInstance); err != nil {
-		r.Log.Error(err, "Could not update GreatSql")
-		return err
+	// Grow PersistentVolumeClaims, the usage of the volumes is checked periodically
+	if storage := SingleInstance.Spec.PodSpec.Storage; storage != nil && storage.AutoGrow != nil {
+		token, err := ensureAgentSecret(ctx, r.Client, SingleInstance, consts.SingleInstance, log)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := autoGrowStorage(ctx, r.Client, r.EventRecorder, SingleInstance, &SingleInstance.Spec.PodSpec, token, func(podName, volume string) string {
+			return kube.StorageClaimName(req.Name, volume)
+		}, log); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: memberSyncInterval}, nil
 	}
 
-	return nil
+	// the progress of the init sql scripts is polled until they are done
+	if initSQL := SingleInstance.Status.InitSQL; initSQL == nil || initSQL.Phase == greatsqlv1.InitSQLRunning {
+		return ctrl.Result{RequeueAfter: memberSyncInterval}, nil
+	}
+	return ctrl.Result{}, nil
 }
 
-// updateResource updates the resource
-func (r *SingleInstanceReconciler) updateResource(ctx context.Context, namespacedName types.NamespacedName, obj client.Object) error {
-	existing := obj.DeepCopyObject().(client.Object)
-	err := r.Client.Get(ctx, namespacedName, existing)
-	existing.SetAnnotations(obj.GetAnnotations())
-	existing.SetLabels(obj.GetLabels())
-	existing.SetOwnerReferences(obj.GetOwnerReferences())
-	existing.SetFinalizers(obj.GetFinalizers())
+// updateStatefulSet applies the statefulset, a SingleInstance always runs one pod
+func (r *SingleInstanceReconciler) updateStatefulSet(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, applier *kube.Applier, log logr.Logger) error {
+	if SingleInstance.Spec.GetSize() > 1 {
+		r.EventRecorder.Eventf(SingleInstance, corev1.EventTypeWarning, "SizeIgnored",
+			"A SingleInstance runs one pod, size %d is ignored", SingleInstance.Spec.GetSize())
+	}
+	sts := kube.NewSingleInstanceStatefulSet(req.Name+"-"+consts.Config, SingleInstance)
+	if err := applier.Apply(ctx, SingleInstance, sts); err != nil {
+		r.Log.Error(err, "Could not apply statefulSet")
+		return err
+	}
+	return nil
+}
 
-	if err != nil {
+// migrateDeployment deletes the Deployment of an instance created before it ran on a statefulset,
+// the PersistentVolumeClaims are kept and mounted by the statefulset. It returns true until the pods
+// of the Deployment are deleted
+func (r *SingleInstanceReconciler) migrateDeployment(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) (bool, error) {
+	deploy := &appsv1.Deployment{}
+	if err := r.Client.Get(ctx, req.NamespacedName, deploy); err != nil {
 		if errors.IsNotFound(err) {
-			if err := r.Client.Create(ctx, obj); err != nil {
-				return fmt.Errorf("could not create resource: %v", err)
-			}
-		} else {
-			return fmt.Errorf("could not get resource: %v", err)
+			return r.hasDeploymentPods(ctx, req, log)
 		}
-	} else {
-		obj.SetResourceVersion(existing.GetResourceVersion())
-		if err := r.Client.Update(ctx, obj); err != nil {
-			return fmt.Errorf("could not update resource: %v", err)
+		log.Error(err, "Unable to fetch deployment")
+		return false, err
+	}
+	if !metav1.IsControlledBy(deploy, SingleInstance) {
+		return false, nil
+	}
+
+	if deploy.DeletionTimestamp == nil {
+		if err := r.Client.Delete(ctx, deploy, client.PropagationPolicy(metav1.DeletePropagationForeground)); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "Could not delete deployment", "Name", deploy.Name)
+			return true, err
 		}
+		log.Info("Delete deployment, the instance is migrated to a statefulSet", "Name", deploy.Name)
+		r.EventRecorder.Event(SingleInstance, corev1.EventTypeNormal, "MigratingToStatefulSet",
+			"The deployment is deleted, the statefulSet mounts the same PersistentVolumeClaims once its pod is stopped")
 	}
+	return true, nil
+}
 
-	return r.Client.Update(ctx, existing)
+// hasDeploymentPods returns true while a pod of the deleted Deployment of the instance is still running
+func (r *SingleInstanceReconciler) hasDeploymentPods(ctx context.Context, req ctrl.Request, log logr.Logger) (bool, error) {
+	pods, err := listMemberPods(ctx, r.Client, req.Namespace, req.Name)
+	if err != nil {
+		log.Error(err, "Could not list pods")
+		return false, err
+	}
+	for _, pod := range pods {
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "ReplicaSet" {
+			log.Info("Waiting for the pod of the deployment to be deleted", "Pod", pod.Name)
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
-// updateDeployment updates the deployment
-func (r *SingleInstanceReconciler) updateDeployment(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
-	newDeployments := kube.NewDeployment(req.Name+consts.Config, SingleInstance, int(*SingleInstance.Spec.Size))
-	if err := r.updateResource(ctx, req.NamespacedName, newDeployments); err != nil {
-		r.Log.Error(err, "Could not update deployment")
+// updateService applies the service and the headless service which gives the pod its stable network identity
+func (r *SingleInstanceReconciler) updateService(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, applier *kube.Applier, log logr.Logger) error {
+	service := kube.NewService(req.Name, req.Namespace, consts.SingleInstance, &SingleInstance.ObjectMeta, SingleInstance.Spec.Ports, SingleInstance.Spec.Type)
+	if err := applier.Apply(ctx, SingleInstance, service); err != nil {
+		r.Log.Error(err, "Could not apply service")
 		return err
 	}
-	return nil
-}
 
-// updateService updates the service
-func (r *SingleInstanceReconciler) updateService(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, log logr.Logger) error {
-	newResources := kube.NewService(req.Name, req.Namespace, consts.SingleInstance, &SingleInstance.ObjectMeta, SingleInstance.Spec.Ports, SingleInstance.Spec.Type)
-	if err := r.updateResource(ctx, req.NamespacedName, newResources); err != nil {
-		r.Log.Error(err, "Could not update service")
+	headless := kube.NewService(req.Name, req.Namespace, consts.SingleInstance, &SingleInstance.ObjectMeta, SingleInstance.Spec.Ports, corev1.ServiceTypeClusterIP)
+	headless.Name = req.Name + consts.HeadlessServiceSuffix
+	headless.Spec.ClusterIP = corev1.ClusterIPNone
+	if err := applier.Apply(ctx, SingleInstance, headless); err != nil {
+		r.Log.Error(err, "Could not apply service", "Name", headless.Name)
 		return err
 	}
 	return nil
 }
 
-// updateConfigMap updates the configMap
-func (r *SingleInstanceReconciler) updateConfigMap(ctx context.Context, req ctrl.Request, log logr.Logger) error {
+// updateConfigMap applies the configMap
+func (r *SingleInstanceReconciler) updateConfigMap(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, applier *kube.Applier, log logr.Logger) error {
 	cnf := &mysql.MySQLConfig{
 		ServerID:                   "0",
 		EnableCluster:              false,
@@ -358,6 +402,7 @@ func (r *SingleInstanceReconciler) updateConfigMap(ctx context.Context, req ctrl
 		ReportHost:                 "",
 		ReportPort:                 3306,
 		InnodbBufferPoolSize:       "1G",
+		StorageLayout:              kube.StorageLayout(&SingleInstance.Spec.PodSpec),
 	}
 	cnfData, err := cnf.String(*cnf)
 	if err != nil {
@@ -365,39 +410,25 @@ func (r *SingleInstanceReconciler) updateConfigMap(ctx context.Context, req ctrl
 		return err
 	}
 
-	newConfigMap := kube.NewConfigMap(req.Name+"-config", req.Namespace, "my.cnf", cnfData)
-	if err := r.updateResource(ctx, types.NamespacedName{Namespace: req.Namespace, Name: req.Name + "-config"}, newConfigMap); err != nil {
-		r.Log.Error(err, "Could not update configMap")
+	configMap := kube.NewConfigMap(req.Name+"-"+consts.Config, req.Namespace, "my.cnf", cnfData)
+	if err := applier.Apply(ctx, SingleInstance, configMap); err != nil {
+		r.Log.Error(err, "Could not apply configMap")
 		return err
 	}
 	return nil
 }
 
-// updateStatus updates the status of the SingleInstance
-func (r *SingleInstanceReconciler) updateStatus(ctx context.Context, singleGreatsql *greatsqlv1.SingleInstance, svc corev1.Service) error {
-	// log := logger.WithValues("Request.Service.Namespace", singleGreatsql.Namespace, "Request.Service.Name", singleGreatsql.Name)
-
-	accessPoint := utils.GetServiceAccessPoint(svc)
-
-	status := &greatsqlv1.SingleInstanceStatus{
-		AccessPoint: accessPoint,
-		Size:        *singleGreatsql.Spec.Size,
-		Ready:       0,
-		Age:         svc.CreationTimestamp.String(),
-	}
-
-	if reflect.DeepEqual(singleGreatsql.Status, status) {
-		return nil
+// updatePodDisruptionBudget creates or updates the PodDisruptionBudget configured in the spec,
+// and deletes it once it is removed from the spec
+func (r *SingleInstanceReconciler) updatePodDisruptionBudget(ctx context.Context, req ctrl.Request, SingleInstance *greatsqlv1.SingleInstance, applier *kube.Applier, log logr.Logger) error {
+	if SingleInstance.Spec.PodDisruptionBudget == nil {
+		return deletePodDisruptionBudget(ctx, r.Client, req.NamespacedName, log)
 	}
-
-	singleGreatsql.Status = *status
-
-	// update status
-	if err := r.Client.Status().Update(ctx, singleGreatsql); err != nil {
-		r.Log.Error(err, "Could not update status")
+	pdb := kube.NewPodDisruptionBudget(req.Name, req.Namespace, consts.SingleInstance, &SingleInstance.ObjectMeta, *SingleInstance.Spec.PodDisruptionBudget)
+	if err := applier.Apply(ctx, SingleInstance, pdb); err != nil {
+		r.Log.Error(err, "Could not apply podDisruptionBudget")
 		return err
 	}
-
 	return nil
 }
 
@@ -405,6 +436,9 @@ func (r *SingleInstanceReconciler) updateStatus(ctx context.Context, singleGreat
 func (r *SingleInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&greatsqlv1.SingleInstance{}).
-		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Complete(r)
 }
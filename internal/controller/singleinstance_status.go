@@ -0,0 +1,192 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+)
+
+// newCondition returns the condition of the owner for its current generation
+func newCondition(owner client.Object, conditionType string, status bool, reason, message string) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: owner.GetGeneration(),
+	}
+	if status {
+		condition.Status = metav1.ConditionTrue
+	}
+	return condition
+}
+
+// isPodReady returns true if the Ready condition of the pod is true
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isStatefulSetProgressing returns true while the statefulset has not rolled out its current revision to every pod
+func isStatefulSetProgressing(sts *appsv1.StatefulSet) bool {
+	var replicas int32 = 1
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	return sts.Status.ObservedGeneration < sts.Generation ||
+		sts.Status.UpdatedReplicas < replicas ||
+		sts.Status.CurrentRevision != sts.Status.UpdateRevision
+}
+
+// updateStatus updates the status of the SingleInstance from its statefulset and from its pods, a pod is ready
+// once its Ready condition is true and mysqld answers the SQL ping of the agent. The error of the reconciliation
+// is reported as the last error and makes the instance Degraded
+func (r *SingleInstanceReconciler) updateStatus(ctx context.Context, singleGreatsql *greatsqlv1.SingleInstance, reconcileErr error) error {
+	status := singleGreatsql.Status.DeepCopy()
+	status.AccessPoint = getServiceAccessPoint(ctx, r.Client, singleGreatsql.Name, singleGreatsql.Namespace)
+	status.Size = singleGreatsql.Spec.GetSize()
+	status.Age = singleGreatsql.CreationTimestamp.String()
+	status.ObservedGeneration = singleGreatsql.Generation
+	status.LastError = ""
+	if reconcileErr != nil {
+		status.LastError = reconcileErr.Error()
+	}
+
+	progressing := false
+	sts := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(singleGreatsql), sts); err != nil {
+		if !errors.IsNotFound(err) {
+			r.Log.Error(err, "Unable to fetch statefulSet")
+			return err
+		}
+		status.StatefulSetStatus = appsv1.StatefulSetStatus{}
+	} else {
+		status.StatefulSetStatus = *sts.Status.DeepCopy()
+		// the conditions and observedGeneration of the statefulset are shadowed by the ones of the instance
+		status.StatefulSetStatus.Conditions = nil
+		status.StatefulSetStatus.ObservedGeneration = 0
+		progressing = isStatefulSetProgressing(sts)
+	}
+
+	pods, err := listMemberPods(ctx, r.Client, singleGreatsql.Namespace, singleGreatsql.Name)
+	if err != nil {
+		r.Log.Error(err, "Could not list pods")
+		return err
+	}
+	token, err := getAgentToken(ctx, r.Client, singleGreatsql)
+	if err != nil {
+		r.Log.Error(err, "Unable to fetch agent secret")
+		return err
+	}
+
+	dataDirs := dataDirFailures(r.EventRecorder, singleGreatsql, singleGreatsql.Status.Conditions, pods)
+
+	var ready int32
+	var notReady []string
+	for i := range pods {
+		pod := &pods[i]
+		if !isPodReady(pod) {
+			notReady = append(notReady, fmt.Sprintf("%s is not ready", pod.Name))
+			continue
+		}
+		agentClient := newAgentClient(pod, token)
+		if agentClient == nil {
+			continue
+		}
+		health, err := agentClient.Health()
+		if err != nil {
+			notReady = append(notReady, fmt.Sprintf("%s: %v", pod.Name, err))
+			continue
+		}
+		if health.Version != "" {
+			status.ServerVersion = health.Version
+		}
+		if !health.Ready {
+			notReady = append(notReady, fmt.Sprintf("%s: %s", pod.Name, health.Message))
+			continue
+		}
+		ready++
+	}
+	status.Ready = ready
+
+	var desired int32 = 1
+	hibernate := singleGreatsql.Spec.Hibernate
+	if hibernate {
+		desired = 0
+	}
+	message := fmt.Sprintf("%d of %d pods are ready", ready, desired)
+	if len(notReady) > 0 {
+		message = fmt.Sprintf("%s: %v", message, notReady)
+	}
+
+	setPausedCondition(&status.Conditions, r.EventRecorder, singleGreatsql, singleGreatsql.Spec.Paused)
+	meta.SetStatusCondition(&status.Conditions, hibernationCondition(r.EventRecorder, singleGreatsql,
+		singleGreatsql.Status.Conditions, hibernate, len(pods)))
+
+	switch {
+	case ready > 0:
+		meta.SetStatusCondition(&status.Conditions, newCondition(singleGreatsql, greatsqlv1.ConditionAvailable, true, "MinimumPodsAvailable", message))
+	case hibernate:
+		meta.SetStatusCondition(&status.Conditions, newCondition(singleGreatsql, greatsqlv1.ConditionAvailable, false, ReasonHibernated, "The instance hibernates"))
+	default:
+		meta.SetStatusCondition(&status.Conditions, newCondition(singleGreatsql, greatsqlv1.ConditionAvailable, false, "NoPodAvailable", message))
+	}
+
+	if progressing {
+		meta.SetStatusCondition(&status.Conditions, newCondition(singleGreatsql, greatsqlv1.ConditionProgressing, true, "RollingOut", "The statefulSet is rolling out"))
+	} else {
+		meta.SetStatusCondition(&status.Conditions, newCondition(singleGreatsql, greatsqlv1.ConditionProgressing, false, "RolloutComplete", "The statefulSet is rolled out"))
+	}
+
+	switch {
+	case reconcileErr != nil:
+		meta.SetStatusCondition(&status.Conditions, newCondition(singleGreatsql, greatsqlv1.ConditionDegraded, true, "ReconcileError", reconcileErr.Error()))
+	case len(dataDirs) > 0:
+		meta.SetStatusCondition(&status.Conditions, newCondition(singleGreatsql, greatsqlv1.ConditionDegraded, true, ReasonDataDirCorrupted, strings.Join(dataDirs, "; ")))
+	case ready < desired && !progressing:
+		meta.SetStatusCondition(&status.Conditions, newCondition(singleGreatsql, greatsqlv1.ConditionDegraded, true, "PodsNotReady", message))
+	default:
+		meta.SetStatusCondition(&status.Conditions, newCondition(singleGreatsql, greatsqlv1.ConditionDegraded, false, "AsExpected", message))
+	}
+
+	if reflect.DeepEqual(singleGreatsql.Status, *status) {
+		return nil
+	}
+
+	singleGreatsql.Status = *status
+	if err := r.Client.Status().Update(ctx, singleGreatsql); err != nil {
+		r.Log.Error(err, "Could not update status")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/agent"
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+	"github.com/go-logr/logr"
+)
+
+// autoGrowStorage grows the PersistentVolumeClaims of the members whose volumes are used above the threshold
+// of the auto-grow policy, one step at a time up to the maximum size. The usage is reported by the agent of each
+// member, claimName returns the claim of the volume of the member
+func autoGrowStorage(ctx context.Context, c client.Client, recorder record.EventRecorder, owner client.Object, podSpec *greatsqlv1.PodSpec,
+	token string, claimName func(podName, volume string) string, log logr.Logger) error {
+	if podSpec.Storage == nil || podSpec.Storage.AutoGrow == nil {
+		return nil
+	}
+	volumes := kube.StorageVolumeMountPaths(podSpec)
+
+	pods, err := listMemberPods(ctx, c, owner.GetNamespace(), owner.GetName())
+	if err != nil {
+		log.Error(err, "Could not list member pods")
+		return err
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		agentClient := newAgentClient(pod, token)
+		if agentClient == nil {
+			continue
+		}
+		usages, err := agentClient.GetDiskUsage()
+		if err != nil {
+			log.Info("Could not get disk usage", "Pod", pod.Name, "error", err)
+			continue
+		}
+
+		for _, usage := range usages {
+			volume, ok := volumes[usage.Path]
+			if !ok || usage.UsedPercent() < int64(podSpec.Storage.AutoGrow.ThresholdPercent) {
+				continue
+			}
+			pvc := &corev1.PersistentVolumeClaim{}
+			if err := c.Get(ctx, client.ObjectKey{Name: claimName(pod.Name, volume), Namespace: owner.GetNamespace()}, pvc); err != nil {
+				if errors.IsNotFound(err) {
+					// an emptyDir volume has no claim
+					continue
+				}
+				log.Error(err, "Unable to fetch persistentVolumeClaim", "Pod", pod.Name, "Volume", volume)
+				return err
+			}
+			if err := growPersistentVolumeClaim(ctx, c, recorder, owner, pvc, usage, podSpec.Storage.AutoGrow, log); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// growPersistentVolumeClaim grows the claim by one step, capped by the maximum size,
+// the claim is left as is while a previous expansion is in progress. Reaching the maximum size is
+// recorded on the claim so that it is reported once, not on every reconcile
+func growPersistentVolumeClaim(ctx context.Context, c client.Client, recorder record.EventRecorder, owner client.Object,
+	pvc *corev1.PersistentVolumeClaim, usage agent.DiskUsage, policy *greatsqlv1.StorageAutoGrow, log logr.Logger) error {
+	requested := pvc.Spec.Resources.Requests.Storage().DeepCopy()
+	if pvc.Status.Capacity.Storage().Cmp(requested) < 0 {
+		return nil
+	}
+	next, grow := kube.AutoGrowSize(requested, policy)
+	if !grow {
+		if requested.Cmp(policy.MaxSize) < 0 || pvc.Annotations[consts.AutoGrowLimitAnnotation] == policy.MaxSize.String() {
+			return nil
+		}
+		patch := client.MergeFrom(pvc.DeepCopy())
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		pvc.Annotations[consts.AutoGrowLimitAnnotation] = policy.MaxSize.String()
+		if err := c.Patch(ctx, pvc, patch); err != nil {
+			log.Error(err, "Could not record the auto-grow limit", "PersistentVolumeClaim", pvc.Name)
+			return err
+		}
+		recorder.Eventf(owner, corev1.EventTypeWarning, "StorageAutoGrowLimit",
+			"%s is %d%% used and has reached the maximum size %s", pvc.Name, usage.UsedPercent(), policy.MaxSize.String())
+		return nil
+	}
+
+	status, err := expandPersistentVolumeClaim(ctx, c, recorder, owner, pvc, next, log)
+	if err != nil || status.State == kube.VolumeExpansionUnsupported {
+		return err
+	}
+	log.Info("Volume is above the auto-grow threshold", "PersistentVolumeClaim", pvc.Name, "UsedPercent", usage.UsedPercent(), "Size", next.String())
+	recorder.Eventf(owner, corev1.EventTypeWarning, "StorageNearlyFull",
+		"%s is %d%% used, growing it from %s to %s", pvc.Name, usage.UsedPercent(), requested.String(), next.String())
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 greatsql.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+	"github.com/go-logr/logr"
+)
+
+// statefulSetClaimName returns the name of the PersistentVolumeClaim created by the statefulset from the template for the pod
+func statefulSetClaimName(template, podName string) string {
+	return fmt.Sprintf("%s-%s", template, podName)
+}
+
+// allowsExpansion returns true if the storage class of the PersistentVolumeClaim allows volume expansion
+func allowsExpansion(ctx context.Context, c client.Client, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false, nil
+	}
+	class := &storagev1.StorageClass{}
+	if err := c.Get(ctx, client.ObjectKey{Name: *pvc.Spec.StorageClassName}, class); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return class.AllowVolumeExpansion != nil && *class.AllowVolumeExpansion, nil
+}
+
+// expandPersistentVolumeClaim requests the wanted size for the PersistentVolumeClaim if the storage class allows it,
+// a claim is never shrunk, and returns the state of the volume. The wanted size a claim can not be expanded to is
+// recorded on the claim so that it is reported once, when the volume becomes ExpansionUnsupported
+func expandPersistentVolumeClaim(ctx context.Context, c client.Client, recorder record.EventRecorder, owner client.Object,
+	pvc *corev1.PersistentVolumeClaim, want resource.Quantity, log logr.Logger) (greatsqlv1.VolumeStatus, error) {
+	if !kube.NeedsExpansion(pvc, want) {
+		return kube.VolumeStatus(pvc, want), nil
+	}
+
+	allowed, err := allowsExpansion(ctx, c, pvc)
+	if err != nil {
+		log.Error(err, "Unable to fetch storageClass", "PersistentVolumeClaim", pvc.Name)
+		return kube.VolumeStatus(pvc, want), err
+	}
+	if !allowed {
+		if pvc.Annotations[consts.ExpansionUnsupportedAnnotation] == want.String() {
+			return kube.VolumeStatus(pvc, want), nil
+		}
+		patch := client.MergeFrom(pvc.DeepCopy())
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		pvc.Annotations[consts.ExpansionUnsupportedAnnotation] = want.String()
+		if err := c.Patch(ctx, pvc, patch); err != nil {
+			log.Error(err, "Could not record the unsupported expansion", "PersistentVolumeClaim", pvc.Name)
+			return kube.VolumeStatus(pvc, want), err
+		}
+		recorder.Eventf(owner, corev1.EventTypeWarning, "VolumeExpansionUnsupported",
+			"Storage class of %s does not allow volume expansion, the claim keeps %s", pvc.Name, pvc.Spec.Resources.Requests.Storage())
+		return kube.VolumeStatus(pvc, want), nil
+	}
+
+	patch := client.MergeFrom(pvc.DeepCopy())
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	from := pvc.Spec.Resources.Requests.Storage().String()
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = want
+	if err := c.Patch(ctx, pvc, patch); err != nil {
+		log.Error(err, "Could not expand persistentVolumeClaim", "Name", pvc.Name)
+		return kube.VolumeStatus(pvc, want), err
+	}
+	log.Info("Expand persistentVolumeClaim is successful", "Name", pvc.Name, "From", from, "To", want.String())
+	recorder.Eventf(owner, corev1.EventTypeNormal, "VolumeExpansion", "Expanding %s from %s to %s", pvc.Name, from, want.String())
+	return kube.VolumeStatus(pvc, want), nil
+}
+
+// memberVolumes returns the state of the PersistentVolumeClaims of the statefulset member
+func memberVolumes(ctx context.Context, c client.Client, namespace, podName string, templates []corev1.PersistentVolumeClaim) []greatsqlv1.VolumeStatus {
+	var volumes []greatsqlv1.VolumeStatus
+	for _, template := range templates {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := c.Get(ctx, client.ObjectKey{Name: statefulSetClaimName(template.Name, podName), Namespace: namespace}, pvc); err != nil {
+			continue
+		}
+		volumes = append(volumes, kube.VolumeStatus(pvc, *template.Spec.Resources.Requests.Storage()))
+	}
+	return volumes
+}
+
+// expandStatefulSetVolumes expands the PersistentVolumeClaims of the members to the size of the desired claim templates.
+// The claim templates of a statefulset are immutable, once every claim has been resized the statefulset is deleted with
+// orphan cascade so that it is applied again with the desired templates while the members keep running.
+// A template whose claims can not be expanded keeps its live size in the desired statefulset.
+// It returns true while the statefulset must not be applied: the claims are being resized or the statefulset is recreated
+func expandStatefulSetVolumes(ctx context.Context, c client.Client, recorder record.EventRecorder, owner client.Object,
+	desired *appsv1.StatefulSet, log logr.Logger) (bool, error) {
+	live := &appsv1.StatefulSet{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(desired), live); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if live.DeletionTimestamp != nil {
+		log.Info("Waiting for the statefulSet to be deleted before it is recreated", "Name", live.Name)
+		return true, nil
+	}
+
+	liveSizes := make(map[string]resource.Quantity, len(live.Spec.VolumeClaimTemplates))
+	for _, template := range live.Spec.VolumeClaimTemplates {
+		liveSizes[template.Name] = *template.Spec.Resources.Requests.Storage()
+	}
+
+	var replicas int32 = 1
+	if live.Spec.Replicas != nil {
+		replicas = *live.Spec.Replicas
+	}
+
+	expanded, resized := false, true
+	for i := range desired.Spec.VolumeClaimTemplates {
+		template := &desired.Spec.VolumeClaimTemplates[i]
+		want := *template.Spec.Resources.Requests.Storage()
+		liveSize, ok := liveSizes[template.Name]
+		if !ok || liveSize.Cmp(want) >= 0 {
+			continue
+		}
+
+		unsupported := false
+		for ordinal := 0; ordinal < int(replicas); ordinal++ {
+			pvc := &corev1.PersistentVolumeClaim{}
+			key := client.ObjectKey{Name: statefulSetClaimName(template.Name, fmt.Sprintf("%s-%d", live.Name, ordinal)), Namespace: live.Namespace}
+			if err := c.Get(ctx, key, pvc); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return true, err
+			}
+			status, err := expandPersistentVolumeClaim(ctx, c, recorder, owner, pvc, want, log)
+			if err != nil {
+				return true, err
+			}
+			switch status.State {
+			case kube.VolumeExpansionUnsupported:
+				unsupported = true
+			case kube.VolumeReady:
+			default:
+				resized = false
+			}
+		}
+		if unsupported {
+			// the template keeps its size, the other changes of the statefulset are still applied
+			template.Spec.Resources.Requests[corev1.ResourceStorage] = liveSize
+			continue
+		}
+		expanded = true
+	}
+	if !expanded {
+		return false, nil
+	}
+	if !resized {
+		log.Info("Waiting for the persistentVolumeClaims to be resized", "StatefulSet", live.Name)
+		return true, nil
+	}
+
+	if err := c.Delete(ctx, live, client.PropagationPolicy(metav1.DeletePropagationOrphan)); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "Could not delete statefulSet", "Name", live.Name)
+		return true, err
+	}
+	log.Info("Volumes are resized, recreate the statefulSet with the new claim templates", "Name", live.Name)
+	recorder.Eventf(owner, corev1.EventTypeNormal, "StatefulSetRecreated",
+		"Volumes of %s are resized, the statefulSet is recreated with the new claim templates", live.Name)
+	return true, nil
+}
@@ -0,0 +1,366 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 16:02:33
+ * @file: client.go
+ * @description: agent http api client, used by the controller
+ */
+
+// BackupErrorTrailer is the trailer carrying the error of a failed backup stream
+const BackupErrorTrailer = "X-Backup-Error"
+
+// HealthStatus is the health of the member
+type HealthStatus struct {
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+	// Version is the server version, empty if mysqld does not answer
+	Version string `json:"version,omitempty"`
+}
+
+// DiskUsage is the usage of the filesystem mounted at the path
+type DiskUsage struct {
+	Path           string `json:"path"`
+	TotalBytes     uint64 `json:"totalBytes"`
+	UsedBytes      uint64 `json:"usedBytes"`
+	AvailableBytes uint64 `json:"availableBytes"`
+}
+
+// UsedPercent returns the used part of the space available to mysqld, as df does
+func (u DiskUsage) UsedPercent() int64 {
+	if u.UsedBytes+u.AvailableBytes == 0 {
+		return 0
+	}
+	return int64(u.UsedBytes * 100 / (u.UsedBytes + u.AvailableBytes))
+}
+
+// PrimaryRequest elects the member with the server_uuid as the primary of the group
+type PrimaryRequest struct {
+	MemberID string `json:"memberId"`
+}
+
+// InitSQLRequest applies the init sql scripts on the member in order, names identify the scripts in the progress
+type InitSQLRequest struct {
+	Names   []string `json:"names"`
+	Scripts []string `json:"scripts"`
+}
+
+// InitSQLProgress is the progress of the init sql scripts on the member, they were not started
+// since the agent started if Started is false
+type InitSQLProgress struct {
+	Started bool `json:"started"`
+	Running bool `json:"running"`
+	// Scripts are the names of the applied scripts
+	Scripts []string `json:"scripts,omitempty"`
+	// Error is the error of the failed script
+	Error string `json:"error,omitempty"`
+}
+
+// ErrorResponse is the body of a failed request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Client is the client of the agent running beside a member
+type Client struct {
+	Host       string
+	Port       int32
+	Token      string
+	HTTPClient *http.Client
+}
+
+// promoteTimeout bounds a promotion, the member applies the relay log already received for up to a minute first
+const promoteTimeout = 90 * time.Second
+
+// groupStartTimeout bounds START GROUP_REPLICATION, it returns once the member has joined the group
+const groupStartTimeout = 60 * time.Second
+
+// NewClient returns a client of the agent of the member
+func NewClient(host, token string) *Client {
+	return &Client{
+		Host:       host,
+		Port:       consts.AgentPort,
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Health returns the health of the member, an unready member is not an error
+func (c *Client) Health() (HealthStatus, error) {
+	var status HealthStatus
+	resp, err := c.do(http.MethodGet, HealthPath)
+	if err != nil {
+		return status, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return status, decodeError(resp)
+	}
+	return status, json.NewDecoder(resp.Body).Decode(&status)
+}
+
+// GetGroupMembers returns the members of the group as seen by the member
+func (c *Client) GetGroupMembers() ([]mysql.GroupMember, error) {
+	var members []mysql.GroupMember
+	return members, c.getJSON(GroupMembersPath, &members)
+}
+
+// StartGroupReplication makes the member join the group, and returns the members of the group as seen by the member
+func (c *Client) StartGroupReplication() ([]mysql.GroupMember, error) {
+	var members []mysql.GroupMember
+	return members, c.postJSON(GroupStartPath, nil, &members, groupStartTimeout)
+}
+
+// BootstrapGroup starts the group on the member alone, and returns the members of the group as seen by the member
+func (c *Client) BootstrapGroup() ([]mysql.GroupMember, error) {
+	var members []mysql.GroupMember
+	return members, c.postJSON(GroupBootstrapPath, nil, &members, groupStartTimeout)
+}
+
+// SetPrimary elects the member as the primary of the group, and returns the members of the group as seen by the member
+func (c *Client) SetPrimary(memberID string) ([]mysql.GroupMember, error) {
+	var members []mysql.GroupMember
+	return members, c.postJSON(GroupPrimaryPath, PrimaryRequest{MemberID: memberID}, &members, groupStartTimeout)
+}
+
+// GetMemberLag returns the applier lag of the member
+func (c *Client) GetMemberLag() (mysql.MemberLag, error) {
+	var lag mysql.MemberLag
+	return lag, c.getJSON(MemberLagPath, &lag)
+}
+
+// GetMemberWeight returns the election weight of the member
+func (c *Client) GetMemberWeight() (mysql.MemberWeight, error) {
+	var weight mysql.MemberWeight
+	return weight, c.getJSON(MemberWeightPath, &weight)
+}
+
+// SetMemberWeight sets and persists the election weight of the member, and returns its effective weight
+func (c *Client) SetMemberWeight(weight int32) (mysql.MemberWeight, error) {
+	var effective mysql.MemberWeight
+	return effective, c.postJSON(MemberWeightPath, mysql.MemberWeight{Weight: weight}, &effective, 0)
+}
+
+// GetCloneProgress returns the stages of the last clone operation of the member
+func (c *Client) GetCloneProgress() ([]mysql.CloneStage, error) {
+	var stages []mysql.CloneStage
+	return stages, c.getJSON(CloneProgressPath, &stages)
+}
+
+// PrepareCloneDonor prepares the member to be cloned and replicated from, and returns its replication state
+func (c *Client) PrepareCloneDonor() (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.postJSON(CloneDonorPath, nil, &status, 0)
+}
+
+// Clone starts to clone the donor into the member, the progress is returned by GetCloneProgress
+func (c *Client) Clone(donor mysql.CloneDonor) error {
+	var stages []mysql.CloneStage
+	return c.postJSON(ClonePath, donor, &stages, 0)
+}
+
+// SetReadOnly makes the member read-only, and returns its replication state
+func (c *Client) SetReadOnly() (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.postJSON(ReadOnlyPath, nil, &status, 0)
+}
+
+// StartInitSQL starts to apply the init sql scripts on the member and returns at once, the progress
+// is returned by GetInitSQLProgress
+func (c *Client) StartInitSQL(names, scripts []string) (InitSQLProgress, error) {
+	var progress InitSQLProgress
+	return progress, c.postJSON(InitSQLPath, InitSQLRequest{Names: names, Scripts: scripts}, &progress, 0)
+}
+
+// GetInitSQLProgress returns the progress of the init sql scripts on the member
+func (c *Client) GetInitSQLProgress() (InitSQLProgress, error) {
+	var progress InitSQLProgress
+	return progress, c.getJSON(InitSQLPath, &progress)
+}
+
+// TailErrorLog returns the last lines of the error log of the member
+func (c *Client) TailErrorLog(lines int) (string, error) {
+	resp, err := c.do(http.MethodGet, ErrorLogPath+"?lines="+strconv.Itoa(lines))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", decodeError(resp)
+	}
+	data, err := io.ReadAll(resp.Body)
+	return string(data), err
+}
+
+// ReloadConfig sets the dynamic variables of my.cnf on the member
+func (c *Client) ReloadConfig() (mysql.ReloadResult, error) {
+	var result mysql.ReloadResult
+	resp, err := c.do(http.MethodPost, ConfigReloadPath)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return result, decodeError(resp)
+	}
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// GetReplicaStatus returns the replication state of the member
+func (c *Client) GetReplicaStatus() (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.getJSON(ReplicationStatusPath, &status)
+}
+
+// StartReplica makes the member a replica of the source, and returns its replication state
+func (c *Client) StartReplica(source mysql.ReplicationSource) (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.postJSON(ReplicationSourcePath, source, &status, 0)
+}
+
+// Promote makes the member the writable source, and returns its replication state
+func (c *Client) Promote(req mysql.PromoteRequest) (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.postJSON(ReplicationPromotePath, req, &status, promoteTimeout)
+}
+
+// GetChannelStatus returns the replication state of the channel of the member
+func (c *Client) GetChannelStatus(channel string) (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.getJSON(ReplicationChannelStatusPath+"?name="+url.QueryEscape(channel), &status)
+}
+
+// StartChannel points the channel of the member to the source group, and returns its replication state
+func (c *Client) StartChannel(channel mysql.ReplicationChannel) (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.postJSON(ReplicationChannelPath, channel, &status, 0)
+}
+
+// PromoteChannel removes the channel of the member and makes it writable, and returns its replication state
+func (c *Client) PromoteChannel(channel mysql.ReplicationChannel) (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.postJSON(ReplicationChannelPromotePath, channel, &status, promoteTimeout)
+}
+
+// ConfigureChannel points the channel of the member to the source group without starting it, and returns its replication state
+func (c *Client) ConfigureChannel(channel mysql.ReplicationChannel) (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.postJSON(ReplicationChannelConfigPath, channel, &status, 0)
+}
+
+// RemoveChannel removes the channel of the member, and returns its replication state
+func (c *Client) RemoveChannel(channel mysql.ReplicationChannel) (mysql.ReplicaStatus, error) {
+	var status mysql.ReplicaStatus
+	return status, c.postJSON(ReplicationChannelRemovePath, channel, &status, 0)
+}
+
+// GetDiskUsage returns the usage of the volumes of the member
+func (c *Client) GetDiskUsage() ([]DiskUsage, error) {
+	var usages []DiskUsage
+	return usages, c.getJSON(DiskPath, &usages)
+}
+
+// BackupStream returns the logical backup stream of the member, the caller reads the body to the end
+// and then checks the BackupErrorTrailer of the response
+func (c *Client) BackupStream() (*http.Response, error) {
+	// the backup runs as long as the data set needs
+	client := *c.HTTPClient
+	client.Timeout = 0
+
+	req, err := c.newRequest(http.MethodGet, BackupStreamPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+	return resp, nil
+}
+
+func (c *Client) getJSON(path string, v interface{}) error {
+	resp, err := c.do(http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// postJSON posts the request, a timeout other than 0 replaces the timeout of the http client
+func (c *Client) postJSON(path string, in, out interface{}, timeout time.Duration) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest(http.MethodPost, path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := *c.HTTPClient
+	if timeout > 0 {
+		client.Timeout = timeout
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) newRequest(method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, fmt.Sprintf("http://%s:%d%s", c.Host, c.Port, path), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	return req, nil
+}
+
+func (c *Client) do(method, path string) (*http.Response, error) {
+	req, err := c.newRequest(method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.HTTPClient.Do(req)
+}
+
+// decodeError returns the error of a failed request
+func decodeError(resp *http.Response) error {
+	var body ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+		return fmt.Errorf("agent request %s failed: %s", resp.Request.URL.Path, resp.Status)
+	}
+	return fmt.Errorf("agent request %s failed: %s", resp.Request.URL.Path, body.Error)
+}
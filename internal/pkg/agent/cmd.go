@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/health"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 16:15:02
+ * @file: cmd.go
+ * @description: agent command, run as the sidecar of the greatsql pod
+ */
+
+var (
+	port       int32
+	mysqlPort  int32
+	group      bool
+	errorLog   string
+	configFile string
+)
+
+var AgentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Serve the admin api of the local GreatSQL member",
+	Run: func(cmd *cobra.Command, args []string) {
+		server := &Server{
+			Token:      os.Getenv(consts.AgentTokenEnv),
+			Client:     health.LocalClient(mysqlPort, 30*time.Second),
+			Group:      group,
+			ErrorLog:   errorLog,
+			ConfigFile: configFile,
+		}
+		if server.Token == "" {
+			fmt.Fprintf(os.Stderr, "agent error: %s is not set\n", consts.AgentTokenEnv)
+			os.Exit(1)
+		}
+
+		if err := server.ListenAndServe(fmt.Sprintf(":%d", port)); err != nil {
+			fmt.Fprintf(os.Stderr, "agent error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	AgentCmd.Flags().Int32Var(&port, "port", consts.AgentPort, "The port the agent api binds to.")
+	AgentCmd.Flags().Int32Var(&mysqlPort, "mysql-port", consts.MysqlPort, "The port of the local mysqld.")
+	AgentCmd.Flags().BoolVar(&group, "group", false, "The member is part of a group replication.")
+	AgentCmd.Flags().StringVar(&errorLog, "error-log", consts.ErrorLogDir, "The path of the error log.")
+	AgentCmd.Flags().StringVar(&configFile, "config-file", consts.ConfigDir+consts.ConfigFile, "The path of my.cnf.")
+}
@@ -0,0 +1,642 @@
+package agent
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/health"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+	"github.com/gagraler/greatsql-operator/internal/utils"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 15:40:17
+ * @file: server.go
+ * @description: agent http api, served by the sidecar of each greatsql pod
+ */
+
+// api paths of the agent
+const (
+	HealthPath         = "/v1/health"
+	GroupMembersPath   = "/v1/group/members"
+	GroupStartPath     = "/v1/group/start"
+	GroupBootstrapPath = "/v1/group/bootstrap"
+	GroupPrimaryPath   = "/v1/group/primary"
+	MemberLagPath      = "/v1/member/lag"
+	MemberWeightPath   = "/v1/member/weight"
+	CloneProgressPath  = "/v1/clone/progress"
+	CloneDonorPath     = "/v1/clone/donor"
+	ClonePath          = "/v1/clone"
+	ReadOnlyPath       = "/v1/readonly"
+	InitSQLPath        = "/v1/sql/init"
+	ErrorLogPath       = "/v1/logs/error"
+	BackupStreamPath   = "/v1/backup/stream"
+	ConfigReloadPath   = "/v1/config/reload"
+	DiskPath           = "/v1/disk"
+
+	ReplicationStatusPath  = "/v1/replication/status"
+	ReplicationSourcePath  = "/v1/replication/source"
+	ReplicationPromotePath = "/v1/replication/promote"
+
+	ReplicationChannelStatusPath  = "/v1/replication/channel/status"
+	ReplicationChannelPath        = "/v1/replication/channel"
+	ReplicationChannelPromotePath = "/v1/replication/channel/promote"
+	ReplicationChannelConfigPath  = "/v1/replication/channel/configure"
+	ReplicationChannelRemovePath  = "/v1/replication/channel/remove"
+)
+
+const (
+	// defaultTailLines is the number of error log lines returned by default
+	defaultTailLines = 100
+	// maxTailBytes bounds the part of the error log read to find the last lines
+	maxTailBytes = 1 << 20
+)
+
+// Server is the agent api server
+type Server struct {
+	// Token is the bearer token required by every endpoint
+	Token string
+	// Client connects to the local mysqld
+	Client *mysql.MySQL
+	// Group is true if the member is part of a group replication
+	Group bool
+	// ErrorLog is the path of the error log
+	ErrorLog string
+	// ConfigFile is the path of my.cnf
+	ConfigFile string
+
+	// initSQLMu guards the progress of the init sql scripts, they are applied at most once by the agent
+	initSQLMu       sync.Mutex
+	initSQLProgress InitSQLProgress
+}
+
+// Handler returns the http handler of the agent api
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthPath, s.get(s.health))
+	mux.HandleFunc(GroupMembersPath, s.get(s.groupMembers))
+	mux.HandleFunc(GroupStartPath, s.post(s.groupStart))
+	mux.HandleFunc(GroupBootstrapPath, s.post(s.groupBootstrap))
+	mux.HandleFunc(GroupPrimaryPath, s.post(s.groupPrimary))
+	mux.HandleFunc(MemberLagPath, s.get(s.memberLag))
+	mux.HandleFunc(MemberWeightPath, s.memberWeight)
+	mux.HandleFunc(CloneProgressPath, s.get(s.cloneProgress))
+	mux.HandleFunc(CloneDonorPath, s.post(s.cloneDonor))
+	mux.HandleFunc(ClonePath, s.post(s.clone))
+	mux.HandleFunc(ReadOnlyPath, s.post(s.readOnly))
+	mux.HandleFunc(InitSQLPath, s.initSQL)
+	mux.HandleFunc(ErrorLogPath, s.get(s.errorLog))
+	mux.HandleFunc(BackupStreamPath, s.get(s.backupStream))
+	mux.HandleFunc(ConfigReloadPath, s.post(s.configReload))
+	mux.HandleFunc(DiskPath, s.get(s.disk))
+	mux.HandleFunc(ReplicationStatusPath, s.get(s.replicationStatus))
+	mux.HandleFunc(ReplicationSourcePath, s.post(s.replicationSource))
+	mux.HandleFunc(ReplicationPromotePath, s.post(s.replicationPromote))
+	mux.HandleFunc(ReplicationChannelStatusPath, s.get(s.channelStatus))
+	mux.HandleFunc(ReplicationChannelPath, s.post(s.channelStart))
+	mux.HandleFunc(ReplicationChannelPromotePath, s.post(s.channelPromote))
+	mux.HandleFunc(ReplicationChannelConfigPath, s.post(s.channelConfigure))
+	mux.HandleFunc(ReplicationChannelRemovePath, s.post(s.channelRemove))
+	return s.authenticate(mux)
+}
+
+// authenticate rejects the requests without the bearer token, the token is compared in constant time
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || s.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("unauthorized"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// get only accepts GET requests
+func (s *Server) get(handler http.HandlerFunc) http.HandlerFunc {
+	return method(http.MethodGet, handler)
+}
+
+// post only accepts POST requests
+func (s *Server) post(handler http.HandlerFunc) http.HandlerFunc {
+	return method(http.MethodPost, handler)
+}
+
+func method(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	checker := &health.Checker{Client: s.Client, Group: s.Group}
+	version, _ := s.Client.GetVersion()
+	if err := checker.Readiness(); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, HealthStatus{Ready: false, Message: err.Error(), Version: version})
+		return
+	}
+	writeJSON(w, http.StatusOK, HealthStatus{Ready: true, Version: version})
+}
+
+func (s *Server) groupMembers(w http.ResponseWriter, r *http.Request) {
+	members, err := s.Client.GetGroupMembers()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, members)
+}
+
+// groupStart makes the member join the group with the default replication channel user as recovery user
+func (s *Server) groupStart(w http.ResponseWriter, r *http.Request) {
+	password, err := utils.Base64Decode(consts.ReplicationChannelPassword)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.Client.StartGroupReplication(consts.ReplicationChannelUser, string(password)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.groupMembers(w, r)
+}
+
+// groupBootstrap starts the group on the member alone with the default replication channel user as recovery user
+func (s *Server) groupBootstrap(w http.ResponseWriter, r *http.Request) {
+	password, err := utils.Base64Decode(consts.ReplicationChannelPassword)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.Client.BootstrapGroup(consts.ReplicationChannelUser, string(password)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.groupMembers(w, r)
+}
+
+// groupPrimary elects the member of the request as the primary of the group
+func (s *Server) groupPrimary(w http.ResponseWriter, r *http.Request) {
+	var req PrimaryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.Client.SetAsPrimary(req.MemberID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.groupMembers(w, r)
+}
+
+func (s *Server) memberLag(w http.ResponseWriter, r *http.Request) {
+	lag, err := s.Client.GetMemberLag()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, lag)
+}
+
+// memberWeight returns the weight of the member, a POST sets and persists it first
+func (s *Server) memberWeight(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req mysql.MemberWeight
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := s.Client.SetMemberWeight(req.Weight); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	weight, err := s.Client.GetMemberWeight()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, weight)
+}
+
+func (s *Server) cloneProgress(w http.ResponseWriter, r *http.Request) {
+	stages, err := s.Client.GetCloneProgress()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stages)
+}
+
+// cloneDonor prepares the member to be cloned and replicated from with the default replication channel user
+func (s *Server) cloneDonor(w http.ResponseWriter, r *http.Request) {
+	password, err := utils.Base64Decode(consts.ReplicationChannelPassword)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.Client.PrepareCloneDonor(consts.ReplicationChannelUser, string(password)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.replicationStatus(w, r)
+}
+
+// clone starts to clone the donor into the member and returns at once, the clone runs as long as the data set
+// needs and mysqld is restarted on the cloned data, its progress is read from the clone progress
+func (s *Server) clone(w http.ResponseWriter, r *http.Request) {
+	var donor mysql.CloneDonor
+	if err := json.NewDecoder(r.Body).Decode(&donor); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	password, err := utils.Base64Decode(consts.ReplicationChannelPassword)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	client := *s.Client
+	client.Timeout = 0
+	go func() {
+		if err := client.CloneInstance(donor, consts.ReplicationChannelUser, string(password)); err != nil {
+			fmt.Fprintf(os.Stderr, "agent error: clone from %s:%d failed: %v\n", donor.Host, donor.Port, err)
+		}
+	}()
+	writeJSON(w, http.StatusOK, []mysql.CloneStage{})
+}
+
+// readOnly makes the member read-only
+func (s *Server) readOnly(w http.ResponseWriter, r *http.Request) {
+	if err := s.Client.SetReadOnly(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.replicationStatus(w, r)
+}
+
+// initSQL returns the progress of the init sql scripts, a POST starts to apply them and returns at once.
+// The scripts run as long as they need, a second POST returns the progress of the first one
+func (s *Server) initSQL(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req InitSQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if len(req.Names) != len(req.Scripts) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%d names for %d scripts", len(req.Names), len(req.Scripts)))
+			return
+		}
+		s.startInitSQL(req)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	s.initSQLMu.Lock()
+	progress := s.initSQLProgress
+	progress.Scripts = append([]string(nil), progress.Scripts...)
+	s.initSQLMu.Unlock()
+	writeJSON(w, http.StatusOK, progress)
+}
+
+// startInitSQL applies the scripts in order in the background unless they were already started,
+// the first failed script stops the run
+func (s *Server) startInitSQL(req InitSQLRequest) {
+	s.initSQLMu.Lock()
+	defer s.initSQLMu.Unlock()
+	if s.initSQLProgress.Started {
+		return
+	}
+	s.initSQLProgress = InitSQLProgress{Started: true, Running: true}
+
+	client := *s.Client
+	client.Timeout = 0
+	go func() {
+		for i, script := range req.Scripts {
+			err := client.ExecScript(script)
+
+			s.initSQLMu.Lock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "agent error: init sql script %s failed: %v\n", req.Names[i], err)
+				s.initSQLProgress.Error = fmt.Sprintf("%s: %v", req.Names[i], err)
+				s.initSQLProgress.Running = false
+				s.initSQLMu.Unlock()
+				return
+			}
+			s.initSQLProgress.Scripts = append(s.initSQLProgress.Scripts, req.Names[i])
+			s.initSQLMu.Unlock()
+		}
+		s.initSQLMu.Lock()
+		s.initSQLProgress.Running = false
+		s.initSQLMu.Unlock()
+	}()
+}
+
+func (s *Server) errorLog(w http.ResponseWriter, r *http.Request) {
+	lines := defaultTailLines
+	if value := r.URL.Query().Get("lines"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid lines %q", value))
+			return
+		}
+		lines = n
+	}
+
+	data, err := tailFile(s.ErrorLog, lines)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(data)
+}
+
+// backupStream streams a logical backup of the member, the exit status of mysqldump
+// is reported in the trailer since the body has already been sent
+func (s *Server) backupStream(w http.ResponseWriter, r *http.Request) {
+	args := []string{
+		"--host=" + s.Client.Host,
+		"--port=" + strconv.Itoa(int(s.Client.Port)),
+		"--user=" + s.Client.UserName,
+		"--all-databases",
+		"--single-transaction",
+		"--routines",
+		"--events",
+		"--triggers",
+		"--set-gtid-purged=ON",
+	}
+	cmd := exec.CommandContext(r.Context(), "mysqldump", args...)
+	cmd.Env = append(os.Environ(), "MYSQL_PWD="+s.Client.Password)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.Stdout = w
+
+	w.Header().Set("Trailer", BackupErrorTrailer)
+	w.Header().Set("Content-Type", "application/sql")
+	w.WriteHeader(http.StatusOK)
+	if err := cmd.Run(); err != nil {
+		w.Header().Set(BackupErrorTrailer, strings.TrimSpace(fmt.Sprintf("%v: %s", err, stderr.String())))
+	}
+}
+
+func (s *Server) configReload(w http.ResponseWriter, r *http.Request) {
+	file, err := os.Open(s.ConfigFile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer file.Close()
+
+	variables, err := mysql.ParseMysqldSection(file)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	result, err := s.Client.ReloadVariables(variables)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// disk reports the usage of the volumes of the storage layout mounted in the pod
+func (s *Server) disk(w http.ResponseWriter, r *http.Request) {
+	usages := []DiskUsage{}
+	for _, path := range []string{consts.DataDir, consts.BinlogDir, consts.RedoDir, consts.UndoDir, consts.TmpDir} {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(path, &stat); err != nil {
+			// the volume is not part of the layout
+			continue
+		}
+		usages = append(usages, DiskUsage{
+			Path:           path,
+			TotalBytes:     stat.Blocks * uint64(stat.Bsize),
+			UsedBytes:      (stat.Blocks - stat.Bfree) * uint64(stat.Bsize),
+			AvailableBytes: stat.Bavail * uint64(stat.Bsize),
+		})
+	}
+	writeJSON(w, http.StatusOK, usages)
+}
+
+func (s *Server) replicationStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.Client.GetReplicaStatus()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// replicationSource makes the member a replica of the source with the default replication channel user
+func (s *Server) replicationSource(w http.ResponseWriter, r *http.Request) {
+	var source mysql.ReplicationSource
+	if err := json.NewDecoder(r.Body).Decode(&source); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	password, err := utils.Base64Decode(consts.ReplicationChannelPassword)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.Client.StartReplica(source, consts.ReplicationChannelUser, string(password)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.replicationStatus(w, r)
+}
+
+// replicationPromote makes the member the writable source and creates the replication channel user
+func (s *Server) replicationPromote(w http.ResponseWriter, r *http.Request) {
+	var req mysql.PromoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	password, err := utils.Base64Decode(consts.ReplicationChannelPassword)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.Client.Promote(req); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.Client.EnsureReplicationUser(consts.ReplicationChannelUser, string(password)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.replicationStatus(w, r)
+}
+
+func (s *Server) channelStatus(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("channel name is required"))
+		return
+	}
+	s.writeChannelStatus(w, name)
+}
+
+// channelStart points the channel to the source group with the default replication channel user
+func (s *Server) channelStart(w http.ResponseWriter, r *http.Request) {
+	var channel mysql.ReplicationChannel
+	if err := json.NewDecoder(r.Body).Decode(&channel); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	password, err := utils.Base64Decode(consts.ReplicationChannelPassword)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.Client.StartChannel(channel, consts.ReplicationChannelUser, string(password)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeChannelStatus(w, channel.Name)
+}
+
+// channelPromote removes the channel and makes the member writable
+func (s *Server) channelPromote(w http.ResponseWriter, r *http.Request) {
+	var channel mysql.ReplicationChannel
+	if err := json.NewDecoder(r.Body).Decode(&channel); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.Client.PromoteChannel(channel); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeChannelStatus(w, channel.Name)
+}
+
+// channelConfigure points the channel to the source group without starting it, for the secondaries of the group
+func (s *Server) channelConfigure(w http.ResponseWriter, r *http.Request) {
+	var channel mysql.ReplicationChannel
+	if err := json.NewDecoder(r.Body).Decode(&channel); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	password, err := utils.Base64Decode(consts.ReplicationChannelPassword)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.Client.ConfigureChannel(channel, consts.ReplicationChannelUser, string(password)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeChannelStatus(w, channel.Name)
+}
+
+// channelRemove removes the channel and keeps the member read-only, for the secondaries of the group
+func (s *Server) channelRemove(w http.ResponseWriter, r *http.Request) {
+	var channel mysql.ReplicationChannel
+	if err := json.NewDecoder(r.Body).Decode(&channel); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.Client.RemoveChannel(channel.Name); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeChannelStatus(w, channel.Name)
+}
+
+func (s *Server) writeChannelStatus(w http.ResponseWriter, channel string) {
+	status, err := s.Client.GetChannelStatus(channel)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// tailFile returns the last lines of the file
+func tailFile(path string, lines int) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - maxTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimRight(data, "\n")
+	for i, n := len(data)-1, 0; i >= 0; i-- {
+		if data[i] == '\n' {
+			n++
+			if n == lines {
+				return append(data[i+1:], '\n'), nil
+			}
+		}
+	}
+	if len(data) == 0 {
+		return data, nil
+	}
+	return append(data, '\n'), nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, ErrorResponse{Error: err.Error()})
+}
+
+// ListenAndServe serves the agent api until the server fails
+func (s *Server) ListenAndServe(addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+	"github.com/gagraler/greatsql-operator/internal/utils"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 14:02:51
+ * @file: cmd.go
+ * @description: health check command, run as the exec probe of the greatsql container
+ */
+
+var (
+	port                   int32
+	group                  bool
+	timeout                time.Duration
+	maxTransactionsInQueue int64
+	maxSecondsBehind       int64
+)
+
+var HealthCheckCmd = &cobra.Command{
+	Use:       "healthcheck [readiness|liveness|startup]",
+	Short:     "Check the health of the local GreatSQL member",
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{"readiness", "liveness", "startup"},
+	Run: func(cmd *cobra.Command, args []string) {
+		checker := &Checker{
+			Client: LocalClient(port, timeout),
+			Group:  group,
+			Lag: mysql.LagThreshold{
+				MaxTransactionsInQueue: maxTransactionsInQueue,
+				MaxSecondsBehind:       maxSecondsBehind,
+			},
+		}
+
+		var err error
+		switch args[0] {
+		case "readiness":
+			err = checker.Readiness()
+		case "liveness":
+			err = checker.Liveness()
+		case "startup":
+			err = checker.Startup()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s check failed: %v\n", args[0], err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	HealthCheckCmd.Flags().Int32Var(&port, "port", consts.MysqlPort, "The port of the local mysqld.")
+	HealthCheckCmd.Flags().BoolVar(&group, "group", false, "Check the member state in the group replication.")
+	HealthCheckCmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "The timeout of the check.")
+	HealthCheckCmd.Flags().Int64Var(&maxTransactionsInQueue, "max-transactions-in-queue", 0,
+		"The applier queue above which a member is not ready, 0 means no limit.")
+	HealthCheckCmd.Flags().Int64Var(&maxSecondsBehind, "max-seconds-behind", 0,
+		"The applier delay above which a member is not ready, 0 means no limit.")
+}
+
+// RootPassword returns the root password of the local mysqld, it is read
+// from the MYSQL_ROOT_PASSWORD env and falls back to the default password
+func RootPassword() string {
+	password := os.Getenv(consts.MySQLRootPassWord)
+	if password == "" {
+		if decoded, err := utils.Base64Decode(consts.MySQLRootPassWordValue); err == nil {
+			password = string(decoded)
+		}
+	}
+	return password
+}
+
+// LocalClient returns a root client of the local mysqld with the password of RootPassword
+func LocalClient(port int32, timeout time.Duration) *mysql.MySQL {
+	return &mysql.MySQL{
+		Host:     "127.0.0.1",
+		Port:     port,
+		UserName: consts.RootUser,
+		Password: RootPassword(),
+		DB:       consts.MySQLDB,
+		Timeout:  timeout,
+	}
+}
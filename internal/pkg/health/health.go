@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	driver "github.com/go-sql-driver/mysql"
+
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 13:41:09
+ * @file: health.go
+ * @description: member readiness, liveness and startup checks
+ */
+
+// Checker checks the health of the local member
+type Checker struct {
+	Client *mysql.MySQL
+	// Group checks the member state in the group replication
+	Group bool
+	// Lag is the acceptable applier lag of a secondary
+	Lag mysql.LagThreshold
+}
+
+// Readiness returns nil when the member can serve clients,
+// a group member has to be ONLINE with an acceptable applier lag
+func (c *Checker) Readiness() error {
+	if !c.Group {
+		return c.Client.Ping()
+	}
+
+	state, err := c.Client.GetMemberState()
+	if err != nil {
+		return fmt.Errorf("could not get member state: %v", err)
+	}
+	return groupReadiness(state, c.Client.GetMemberLag, c.Lag)
+}
+
+// groupReadiness returns nil when the member is ONLINE and its applier lag is accepted by the threshold,
+// the lag is only fetched for an ONLINE member
+func groupReadiness(state string, memberLag func() (mysql.MemberLag, error), threshold mysql.LagThreshold) error {
+	if state != mysql.MemberStateOnline {
+		return fmt.Errorf("member state is %s", state)
+	}
+
+	lag, err := memberLag()
+	if err != nil {
+		return fmt.Errorf("could not get member lag: %v", err)
+	}
+	if !threshold.ServeReads(true, lag) {
+		return fmt.Errorf("member is lagging, %d transactions in queue, %d seconds behind", lag.TransactionsInQueue, lag.SecondsBehind)
+	}
+	return nil
+}
+
+// Liveness returns an error only when mysqld is hung,
+// i.e. it does not answer within the timeout. Any answer of the server,
+// even an error such as too many connections, means mysqld is alive.
+func (c *Checker) Liveness() error {
+	err := c.Client.Ping()
+	if err == nil || !isHung(err) {
+		return nil
+	}
+	return fmt.Errorf("mysqld does not respond: %v", err)
+}
+
+// Startup returns nil once mysqld accepts queries, clone and crash recovery
+// are covered by the failure threshold of the startup probe
+func (c *Checker) Startup() error {
+	return c.Client.Ping()
+}
+
+// isHung returns true if the error is a timeout rather than an answer of the server
+func isHung(err error) bool {
+	var serverErr *driver.MySQLError
+	if errors.As(err, &serverErr) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrInvalidConn)
+}
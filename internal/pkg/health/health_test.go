@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	driver "github.com/go-sql-driver/mysql"
+
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-20 10:46:52
+ * @file: health_test.go
+ * @description: member readiness and liveness checks test
+ */
+
+func TestGroupReadiness(t *testing.T) {
+	threshold := mysql.LagThreshold{MaxTransactionsInQueue: 100, MaxSecondsBehind: 30}
+	lag := func(queue, seconds int64) func() (mysql.MemberLag, error) {
+		return func() (mysql.MemberLag, error) {
+			return mysql.MemberLag{TransactionsInQueue: queue, SecondsBehind: seconds}, nil
+		}
+	}
+
+	tests := []struct {
+		name  string
+		state string
+		lag   func() (mysql.MemberLag, error)
+		ready bool
+	}{
+		{name: "online member", state: mysql.MemberStateOnline, lag: lag(0, 0), ready: true},
+		{name: "online member within the threshold", state: mysql.MemberStateOnline, lag: lag(100, 30), ready: true},
+		{name: "lagging member", state: mysql.MemberStateOnline, lag: lag(101, 0), ready: false},
+		{name: "recovering member", state: "RECOVERING", lag: lag(0, 0), ready: false},
+		{name: "member out of the group", state: "OFFLINE", lag: lag(0, 0), ready: false},
+		{name: "unknown lag", state: mysql.MemberStateOnline, lag: func() (mysql.MemberLag, error) {
+			return mysql.MemberLag{}, errors.New("connection refused")
+		}, ready: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := groupReadiness(tt.state, tt.lag, threshold)
+			if (err == nil) != tt.ready {
+				t.Errorf("groupReadiness() error = %v, want ready %v", err, tt.ready)
+			}
+		})
+	}
+}
+
+func TestIsHung(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "server answer", err: &driver.MySQLError{Number: 1040, Message: "Too many connections"}, want: false},
+		{name: "deadline exceeded", err: fmt.Errorf("ping: %w", context.DeadlineExceeded), want: true},
+		{name: "invalid connection", err: driver.ErrInvalidConn, want: true},
+		{name: "connection refused", err: errors.New("connection refused"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHung(tt.err); got != tt.want {
+				t.Errorf("isHung() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package kube
+
+import (
+	"path/filepath"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 16:31:18
+ * @file: agent.go
+ * @description: agent sidecar of the greatsql pod
+ */
+
+// AgentSecretName returns the name of the secret holding the agent token
+func AgentSecretName(name string) string {
+	return name + consts.AgentSecretSuffix
+}
+
+// NewAgentSecret returns the secret holding the agent token of the instance
+func NewAgentSecret(name, namespace, token string, owner metav1.OwnerReference) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AgentSecretName(name),
+			Namespace: namespace,
+			Labels: map[string]string{
+				consts.AppKubernetesName:      name,
+				consts.AppKubernetesInstance:  name,
+				consts.AppKubernetesComponent: consts.AgentContainerName,
+			},
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Data: map[string][]byte{
+			consts.AgentTokenKey: []byte(token),
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+}
+
+// NewAgentContainer returns the agent sidecar, it runs the operator binary of the tools volume
+// in the greatsql image, so that it shares the data dir, the config and the client tools of the member
+func NewAgentContainer(greatsql corev1.Container, name string, group bool) corev1.Container {
+	command := []string{filepath.Join(consts.ToolsDir, consts.ToolsBinary), "agent"}
+	if group {
+		command = append(command, "--group")
+	}
+
+	env := append([]corev1.EnvVar{}, greatsql.Env...)
+	env = append(env, corev1.EnvVar{
+		Name: consts.AgentTokenEnv,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: AgentSecretName(name)},
+				Key:                  consts.AgentTokenKey,
+			},
+		},
+	})
+
+	return corev1.Container{
+		Name:            consts.AgentContainerName,
+		Image:           greatsql.Image,
+		ImagePullPolicy: greatsql.ImagePullPolicy,
+		Command:         command,
+		Env:             env,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          consts.AgentPortName,
+				ContainerPort: consts.AgentPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+		SecurityContext: greatsql.SecurityContext,
+		VolumeMounts:    append([]corev1.VolumeMount{}, greatsql.VolumeMounts...),
+	}
+}
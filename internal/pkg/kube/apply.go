@@ -0,0 +1,142 @@
+package kube
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 19:05:26
+ * @file: apply.go
+ * @description: server-side apply of the owned resources with drift correction
+ */
+
+// FieldOwner is the field manager of the operator, only the fields it applies are owned,
+// the fields set by users or other controllers are left untouched
+const FieldOwner = "greatsql-operator"
+
+// Applier applies the desired state of the owned resources with server-side apply
+type Applier struct {
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+}
+
+// NewApplier returns an applier recording drift events with the given recorder
+func NewApplier(c client.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *Applier {
+	return &Applier{Client: c, Scheme: scheme, EventRecorder: recorder}
+}
+
+// Apply applies the desired object owned by the owner. An object whose applied hash is unchanged
+// but which would still be changed by the apply has been edited out of band,
+// the edit is reverted and an event is recorded on the owner.
+func (a *Applier) Apply(ctx context.Context, owner client.Object, obj client.Object) error {
+	if len(obj.GetOwnerReferences()) == 0 {
+		if err := controllerutil.SetControllerReference(owner, obj, a.Scheme); err != nil {
+			return err
+		}
+	}
+
+	hash, err := appliedHash(obj)
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[consts.AppliedHashAnnotation] = hash
+	obj.SetAnnotations(annotations)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	empty, err := a.Scheme.New(gvk)
+	if err != nil {
+		return err
+	}
+	live := empty.(client.Object)
+	err = a.Client.Get(ctx, client.ObjectKeyFromObject(obj), live)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if exists && live.GetAnnotations()[consts.AppliedHashAnnotation] == hash {
+		drifted, err := a.drifted(ctx, live, obj)
+		if err != nil {
+			return err
+		}
+		if !drifted {
+			return nil
+		}
+		a.EventRecorder.Eventf(owner, corev1.EventTypeWarning, "DriftCorrected",
+			"%s %s was modified outside of the operator, the change has been reverted",
+			gvk.Kind, obj.GetName())
+	}
+
+	return a.Client.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldOwner), client.ForceOwnership)
+}
+
+// drifted returns true if applying the desired object would change the live object,
+// the apply is sent as a dry run so nothing is persisted
+func (a *Applier) drifted(ctx context.Context, live, desired client.Object) (bool, error) {
+	dryRun := desired.DeepCopyObject().(client.Object)
+	if err := a.Client.Patch(ctx, dryRun, client.Apply, client.FieldOwner(FieldOwner), client.ForceOwnership, client.DryRunAll); err != nil {
+		return false, err
+	}
+
+	before, err := comparable(live)
+	if err != nil {
+		return false, err
+	}
+	after, err := comparable(dryRun)
+	if err != nil {
+		return false, err
+	}
+	return !reflect.DeepEqual(before, after), nil
+}
+
+// comparable returns the object without the fields the server changes on every write
+func comparable(obj client.Object) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range []string{"resourceVersion", "generation", "managedFields"} {
+		unstructured.RemoveNestedField(content, "metadata", field)
+	}
+	unstructured.RemoveNestedField(content, "status")
+	unstructured.RemoveNestedField(content, "apiVersion")
+	unstructured.RemoveNestedField(content, "kind")
+	return content, nil
+}
+
+// appliedHash returns the hash of the desired object
+func appliedHash(obj client.Object) (string, error) {
+	copied := obj.DeepCopyObject().(client.Object)
+	annotations := copied.GetAnnotations()
+	delete(annotations, consts.AppliedHashAnnotation)
+	copied.SetAnnotations(annotations)
+
+	data, err := json.Marshal(copied)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal %s: %v", obj.GetName(), err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,151 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-20 10:21:37
+ * @file: apply_test.go
+ * @description: server-side apply with drift correction test
+ */
+
+// applyCalls counts the apply patches sent by the applier, the dry runs apart
+type applyCalls struct {
+	applies, dryRuns int
+}
+
+// newTestApplier returns an applier on a fake client holding the objects, an apply of a missing object creates it
+func newTestApplier(t *testing.T, calls *applyCalls, objs ...client.Object) (*Applier, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			options := &client.PatchOptions{}
+			options.ApplyOptions(opts)
+			if len(options.DryRun) > 0 {
+				calls.dryRuns++
+			} else {
+				calls.applies++
+			}
+			err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj.DeepCopyObject().(client.Object))
+			if apierrors.IsNotFound(err) && len(options.DryRun) == 0 {
+				return c.Create(ctx, obj)
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}).Build()
+	recorder := record.NewFakeRecorder(10)
+	return NewApplier(c, scheme, recorder), recorder
+}
+
+func TestApplierApply(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "mgr", Namespace: "default", UID: "6d1b5a4e"}}
+	desired := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "mgr-config", Namespace: "default"},
+			Data:       map[string]string{"my.cnf": value},
+		}
+	}
+	// applied returns the live object as the applier left it after applying the desired value
+	applied := func(t *testing.T, value string) *corev1.ConfigMap {
+		calls := &applyCalls{}
+		applier, _ := newTestApplier(t, calls)
+		obj := desired(value)
+		if err := applier.Apply(context.Background(), owner, obj); err != nil {
+			t.Fatal(err)
+		}
+		live := &corev1.ConfigMap{}
+		if err := applier.Client.Get(context.Background(), client.ObjectKeyFromObject(obj), live); err != nil {
+			t.Fatal(err)
+		}
+		live.ResourceVersion = ""
+		return live
+	}
+
+	tests := []struct {
+		name    string
+		live    func(t *testing.T) *corev1.ConfigMap
+		value   string
+		want    applyCalls
+		drifted bool
+	}{
+		{
+			name:  "missing object is applied",
+			live:  func(t *testing.T) *corev1.ConfigMap { return nil },
+			value: "server_id=1",
+			want:  applyCalls{applies: 1},
+		},
+		{
+			name:  "unchanged object is left as is",
+			live:  func(t *testing.T) *corev1.ConfigMap { return applied(t, "server_id=1") },
+			value: "server_id=1",
+			want:  applyCalls{dryRuns: 1},
+		},
+		{
+			name: "object edited out of band is reverted",
+			live: func(t *testing.T) *corev1.ConfigMap {
+				live := applied(t, "server_id=1")
+				live.Data["my.cnf"] = "server_id=2"
+				return live
+			},
+			value:   "server_id=1",
+			want:    applyCalls{dryRuns: 1, applies: 1},
+			drifted: true,
+		},
+		{
+			name:  "changed desired state is applied without a dry run",
+			live:  func(t *testing.T) *corev1.ConfigMap { return applied(t, "server_id=1") },
+			value: "server_id=3",
+			want:  applyCalls{applies: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []client.Object
+			if live := tt.live(t); live != nil {
+				objs = append(objs, live)
+			}
+			calls := &applyCalls{}
+			applier, recorder := newTestApplier(t, calls, objs...)
+
+			obj := desired(tt.value)
+			if err := applier.Apply(context.Background(), owner, obj); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if *calls != tt.want {
+				t.Errorf("Apply() calls = %+v, want %+v", *calls, tt.want)
+			}
+			if drifted := len(recorder.Events) > 0; drifted != tt.drifted {
+				t.Errorf("Apply() drift event = %v, want %v", drifted, tt.drifted)
+			}
+
+			live := &corev1.ConfigMap{}
+			if err := applier.Client.Get(context.Background(), client.ObjectKeyFromObject(obj), live); err != nil {
+				t.Fatal(err)
+			}
+			if live.Data["my.cnf"] != tt.value {
+				t.Errorf("Apply() data = %q, want %q", live.Data["my.cnf"], tt.value)
+			}
+			if live.Annotations[consts.AppliedHashAnnotation] == "" {
+				t.Errorf("Apply() did not record the applied hash")
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package kube
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	corev1 "k8s.io/api/core/v1"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 17:10:45
+ * @file: lifecycle.go
+ * @description: preStop hook of the greatsql container
+ */
+
+const (
+	// defaultTerminationGracePeriodSeconds is the grace period of the kubelet when the pod spec leaves it empty
+	defaultTerminationGracePeriodSeconds int64 = 30
+	// preStopMarginSeconds is kept for mysqld to exit once the hook returns
+	preStopMarginSeconds int64 = 5
+)
+
+// SetPreStopHook sets the preStop hook of the greatsql container, the member hands over the primary role,
+// leaves the group and shuts down within the termination grace period of the pod
+func SetPreStopHook(container *corev1.Container, group bool, terminationGracePeriodSeconds *int64) {
+	grace := defaultTerminationGracePeriodSeconds
+	if terminationGracePeriodSeconds != nil {
+		grace = *terminationGracePeriodSeconds
+	}
+	timeout := grace - preStopMarginSeconds
+	if timeout < 1 {
+		timeout = 1
+	}
+
+	command := []string{filepath.Join(consts.ToolsDir, consts.ToolsBinary), "prestop", "--timeout=" + strconv.FormatInt(timeout, 10) + "s"}
+	if group {
+		command = append(command, "--group")
+	}
+
+	if container.Lifecycle == nil {
+		container.Lifecycle = &corev1.Lifecycle{}
+	}
+	container.Lifecycle.PreStop = &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{Command: command},
+	}
+}
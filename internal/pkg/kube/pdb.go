@@ -0,0 +1,57 @@
+package kube
+
+import (
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 17:26:03
+ * @file: pdb.go
+ * @description: kubernetes pod disruption budget operation
+ */
+
+// NewPodDisruptionBudget returns a PodDisruptionBudget selecting the pods of the instance
+func NewPodDisruptionBudget(name, namespace, kind string, objectMeta metav1.Object, spec greatsqlv1.PodDisruptionBudgetSpec) *policyv1.PodDisruptionBudget {
+	labels := map[string]string{
+		consts.AppKubernetesName:     name,
+		consts.AppKubernetesInstance: name,
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(objectMeta, schema.GroupVersionKind{
+					Group:   greatsqlv1.GroupVersion.Group,
+					Version: greatsqlv1.GroupVersion.Version,
+					Kind:    kind,
+				}),
+			},
+			Labels: labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector:       &metav1.LabelSelector{MatchLabels: labels},
+			MinAvailable:   spec.MinAvailable,
+			MaxUnavailable: spec.MaxUnavailable,
+		},
+	}
+}
+
+// GroupMaxUnavailable returns the members which can be evicted at once while the group keeps its majority
+func GroupMaxUnavailable(size int32) intstr.IntOrString {
+	if size < 1 {
+		return intstr.FromInt32(0)
+	}
+	return intstr.FromInt32((size - 1) / 2)
+}
@@ -0,0 +1,30 @@
+package kube
+
+import "testing"
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-20 09:48:21
+ * @file: pdb_test.go
+ * @description: kubernetes pod disruption budget test
+ */
+
+func TestGroupMaxUnavailable(t *testing.T) {
+	tests := []struct {
+		size int32
+		want int
+	}{
+		{size: 0, want: 0},
+		{size: 1, want: 0},
+		{size: 2, want: 0},
+		{size: 3, want: 1},
+		{size: 4, want: 1},
+		{size: 5, want: 2},
+		{size: 9, want: 4},
+	}
+	for _, tt := range tests {
+		if got := GroupMaxUnavailable(tt.size); got.IntValue() != tt.want {
+			t.Errorf("GroupMaxUnavailable(%d) = %d, want %d", tt.size, got.IntValue(), tt.want)
+		}
+	}
+}
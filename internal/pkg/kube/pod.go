@@ -2,6 +2,7 @@ package kube
 
 import (
 	"fmt"
+	"strconv"
 
 	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
 	"github.com/gagraler/greatsql-operator/internal/consts"
@@ -16,28 +17,15 @@ import (
  * @description: kubernetes pod operation
  */
 
-// NewContainers returns a new container
-func NewContainers(name string, cr *greatsqlv1.PodSpec, ordinal int, isStatefulSet bool) []corev1.Container {
-
-	var volumeMounts []corev1.VolumeMount
+// NewContainers returns a new container, my.cnf is mounted from the config volume
+func NewContainers(name string, cr *greatsqlv1.PodSpec, configVolume string, ordinal int, isStatefulSet bool) []corev1.Container {
 
 	configVolumeMount := corev1.VolumeMount{
-		Name:      fmt.Sprintf("%s-%s", name, consts.Config),
+		Name:      configVolume,
 		MountPath: consts.ConfigDir + consts.ConfigFile,
 		SubPath:   consts.ConfigFile,
 	}
-
-	dbVolumeMount := corev1.VolumeMount{
-		Name:      fmt.Sprintf("%s-%s", name, consts.DB),
-		MountPath: consts.DB,
-	}
-
-	if isStatefulSet {
-		configVolumeMount.Name = fmt.Sprintf("%s-%s-%d", name, consts.Config, ordinal)
-		dbVolumeMount.Name = fmt.Sprintf("%s-%s-%d", name, consts.DB, ordinal)
-	}
-
-	volumeMounts = append(volumeMounts, configVolumeMount, dbVolumeMount)
+	volumeMounts := append([]corev1.VolumeMount{configVolumeMount}, NewStorageVolumeMounts(name, cr, ordinal, isStatefulSet)...)
 
 	return []corev1.Container{
 		{
@@ -62,6 +50,46 @@ func NewContainers(name string, cr *greatsqlv1.PodSpec, ordinal int, isStatefulS
 	}
 }
 
+// NewInitContainers returns the init container which renders my.cnf of the member from the config template shared
+// by the group, the server_id is taken from the ordinal of the pod and the report_host from its FQDN
+func NewInitContainers(name string, cr *greatsqlv1.PodSpec, serverIDBase uint32) []corev1.Container {
+	container := corev1.Container{
+		Name:  consts.ConfigInitContainer,
+		Image: OperatorImage(),
+		Command: []string{"/" + consts.ToolsBinary, "render-config",
+			"--template", consts.ConfigTemplateDir + consts.ConfigFile,
+			"--output", consts.ConfigRenderDir + consts.ConfigFile,
+			"--server-id-base", strconv.FormatUint(uint64(serverIDBase), 10),
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name: consts.PodNameEnv,
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: ConfigTemplateVolumeName(name), MountPath: consts.ConfigTemplateDir},
+			{Name: RenderedConfigVolumeName(name), MountPath: consts.ConfigRenderDir},
+		},
+	}
+	if len(cr.Containers) > 0 {
+		container.ImagePullPolicy = cr.Containers[0].ImagePullPolicy
+	}
+	return []corev1.Container{container}
+}
+
+// ConfigTemplateVolumeName returns the name of the volume of the config template shared by the group
+func ConfigTemplateVolumeName(name string) string {
+	return fmt.Sprintf("%s-%s-template", name, consts.Config)
+}
+
+// RenderedConfigVolumeName returns the name of the emptyDir volume of my.cnf rendered for the member
+func RenderedConfigVolumeName(name string) string {
+	return fmt.Sprintf("%s-%s-rendered", name, consts.Config)
+}
+
 func NewPod(configMapName string, cr *greatsqlv1.GroupReplicationCluster, ordinal int) corev1.Pod {
 
 	return corev1.Pod{
@@ -78,16 +106,16 @@ func NewPod(configMapName string, cr *greatsqlv1.GroupReplicationCluster, ordina
 			},
 		},
 		Spec: corev1.PodSpec{
-			Containers:                    NewContainers(cr.Name, cr.Spec.ClusterSpec.PodSpec, ordinal, false),
+			Containers:                    NewContainers(cr.Name, cr.Spec.ClusterSpec.PodSpec, StorageVolumeName(cr.Name, consts.Config, ordinal, false), ordinal, false),
 			TerminationGracePeriodSeconds: cr.Spec.ClusterSpec.PodSpec.TerminationGracePeriodSeconds,
 			SchedulerName:                 cr.Spec.ClusterSpec.PodSpec.SchedulerName,
 			ServiceAccountName:            cr.Spec.ClusterSpec.PodSpec.ServiceAccountName,
 			SecurityContext:               cr.Spec.ClusterSpec.PodSpec.PodSecurityContext,
 			NodeSelector:                  cr.Spec.ClusterSpec.PodSpec.NodeSelector,
 			Tolerations:                   cr.Spec.ClusterSpec.PodSpec.Tolerations,
-			Volumes: []corev1.Volume{
+			Volumes: append([]corev1.Volume{
 				{
-					Name: cr.Name + consts.Config,
+					Name: StorageVolumeName(cr.Name, consts.Config, ordinal, false),
 					VolumeSource: corev1.VolumeSource{
 						ConfigMap: &corev1.ConfigMapVolumeSource{
 							LocalObjectReference: corev1.LocalObjectReference{
@@ -97,15 +125,7 @@ func NewPod(configMapName string, cr *greatsqlv1.GroupReplicationCluster, ordina
 						},
 					},
 				},
-				{
-					Name: cr.Name + consts.DB,
-					VolumeSource: corev1.VolumeSource{
-						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-							ClaimName: cr.Name + consts.DB,
-						},
-					},
-				},
-			},
+			}, NewClaimVolumes(cr.Name, cr.Spec.ClusterSpec.PodSpec)...),
 			DNSPolicy: cr.Spec.ClusterSpec.DnsPolicy,
 		},
 	}
@@ -127,6 +147,11 @@ func GetPodDNS(pod *corev1.Pod) string {
 	return ""
 }
 
+// GetPodFQDN returns the fully qualified domain name of a pod governed by the headless service
+func GetPodFQDN(podName, serviceName, namespace string) string {
+	return fmt.Sprintf("%s.%s.%s.svc.cluster.local", podName, serviceName, namespace)
+}
+
 // GetPodIP returns the pod ip of the pod
 func GetPodIP(pod *corev1.Pod) string {
 	if pod.Status.PodIP != "" {
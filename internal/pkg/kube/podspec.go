@@ -0,0 +1,72 @@
+package kube
+
+import (
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	corev1 "k8s.io/api/core/v1"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 23:38:15
+ * @file: podspec.go
+ * @description: user init containers, sidecars, volumes and scheduling fields merged into the pod template
+ */
+
+// MergePodSpec merges the additions of the user into the pod built by the operator in a stable order: the init
+// containers after the init containers of the operator, once the config is rendered and the data dir is initialized,
+// the sidecars after the greatsql and agent containers, the extra volumes after the volumes of the operator,
+// and the extra volume mounts after the ones of the greatsql container.
+// The data dir init container and the agent copy the mounts of the greatsql container, they get the extra ones as well
+func MergePodSpec(pod *corev1.PodSpec, podSpec *greatsqlv1.PodSpec) {
+	pod.ImagePullSecrets = ImagePullSecrets(podSpec)
+	pod.PriorityClassName = podSpec.PriorityClassName
+	pod.RuntimeClassName = podSpec.RuntimeClassName
+
+	for _, container := range podSpec.InitContainers {
+		pod.InitContainers = append(pod.InitContainers, *container.DeepCopy())
+	}
+	for _, container := range podSpec.Sidecars {
+		pod.Containers = append(pod.Containers, *container.DeepCopy())
+	}
+	for _, volume := range podSpec.ExtraVolumes {
+		pod.Volumes = append(pod.Volumes, *volume.DeepCopy())
+	}
+	for i := range pod.InitContainers {
+		if pod.InitContainers[i].Name == consts.DataDirInitContainer {
+			pod.InitContainers[i].VolumeMounts = appendVolumeMounts(pod.InitContainers[i].VolumeMounts, podSpec.ExtraVolumeMounts)
+		}
+	}
+	for i := range pod.Containers {
+		if i == 0 || pod.Containers[i].Name == consts.AgentContainerName {
+			pod.Containers[i].VolumeMounts = appendVolumeMounts(pod.Containers[i].VolumeMounts, podSpec.ExtraVolumeMounts)
+		}
+	}
+}
+
+// appendVolumeMounts returns a copy of the mounts followed by the extra ones, the containers never share a slice
+func appendVolumeMounts(mounts, extra []corev1.VolumeMount) []corev1.VolumeMount {
+	return append(append([]corev1.VolumeMount(nil), mounts...), extra...)
+}
+
+// ImagePullSecrets returns the image pull secrets of the pod followed by the ones of the containers,
+// each secret once, nil if there is none
+func ImagePullSecrets(podSpec *greatsqlv1.PodSpec) []corev1.LocalObjectReference {
+	var secrets []corev1.LocalObjectReference
+	seen := make(map[string]bool)
+	add := func(refs []corev1.LocalObjectReference) {
+		for _, ref := range refs {
+			if ref.Name == "" || seen[ref.Name] {
+				continue
+			}
+			seen[ref.Name] = true
+			secrets = append(secrets, ref)
+		}
+	}
+
+	add(podSpec.ImagePullSecrets)
+	for _, container := range podSpec.Containers {
+		add(container.ImagePullSecrets)
+	}
+	return secrets
+}
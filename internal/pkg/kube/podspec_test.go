@@ -0,0 +1,72 @@
+package kube
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-20 02:14:08
+ * @file: podspec_test.go
+ * @description: pod spec merge test
+ */
+
+func TestMergePodSpec(t *testing.T) {
+	podSpec := &greatsqlv1.PodSpec{
+		InitContainers:    []corev1.Container{{Name: "seed"}},
+		Sidecars:          []corev1.Container{{Name: "log-shipper"}},
+		ExtraVolumes:      []corev1.Volume{{Name: "certs"}},
+		ExtraVolumeMounts: []corev1.VolumeMount{{Name: "certs", MountPath: "/etc/certs"}},
+	}
+	pod := &corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "greatsql", VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}}}},
+	}
+	InjectTools(pod, podSpec, "mgr", true, nil)
+	MergePodSpec(pod, podSpec)
+
+	wantInit := []string{consts.ToolsVolume, consts.DataDirInitContainer, "seed"}
+	if len(pod.InitContainers) != len(wantInit) {
+		t.Fatalf("MergePodSpec() init containers = %d, want %v", len(pod.InitContainers), wantInit)
+	}
+	for i, name := range wantInit {
+		if pod.InitContainers[i].Name != name {
+			t.Errorf("MergePodSpec() init container %d = %s, want %s", i, pod.InitContainers[i].Name, name)
+		}
+	}
+	wantContainers := []string{"greatsql", consts.AgentContainerName, "log-shipper"}
+	if len(pod.Containers) != len(wantContainers) {
+		t.Fatalf("MergePodSpec() containers = %d, want %v", len(pod.Containers), wantContainers)
+	}
+	for i, name := range wantContainers {
+		if pod.Containers[i].Name != name {
+			t.Errorf("MergePodSpec() container %d = %s, want %s", i, pod.Containers[i].Name, name)
+		}
+	}
+	if last := pod.Volumes[len(pod.Volumes)-1]; last.Name != "certs" {
+		t.Errorf("MergePodSpec() last volume = %s, want certs", last.Name)
+	}
+
+	// the containers which see the data of mysqld get the extra mounts once, the tools and the user containers do not
+	for _, container := range []corev1.Container{pod.InitContainers[0], pod.InitContainers[1], pod.InitContainers[2],
+		pod.Containers[0], pod.Containers[1], pod.Containers[2]} {
+		want := 0
+		switch container.Name {
+		case "greatsql", consts.DataDirInitContainer, consts.AgentContainerName:
+			want = 1
+		}
+		got := 0
+		for _, mount := range container.VolumeMounts {
+			if mount.Name == "certs" {
+				got++
+			}
+		}
+		if got != want {
+			t.Errorf("MergePodSpec() container %s mounts certs %d times, want %d", container.Name, got, want)
+		}
+	}
+}
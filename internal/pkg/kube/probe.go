@@ -0,0 +1,82 @@
+package kube
+
+import (
+	"path/filepath"
+	"strconv"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	corev1 "k8s.io/api/core/v1"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 14:36:12
+ * @file: probe.go
+ * @description: built-in exec probes of the greatsql container
+ */
+
+// BuiltinProbesEnabled returns true unless the built-in probes are disabled in the pod spec
+func BuiltinProbesEnabled(podSpec *greatsqlv1.PodSpec) bool {
+	return podSpec.BuiltinProbes == nil || *podSpec.BuiltinProbes
+}
+
+// SetBuiltinProbes replaces the probe handlers of the greatsql container with the health check command
+// of the tools volume, group checks the member state and the lag policy in the group replication
+func SetBuiltinProbes(container *corev1.Container, group bool, lag *greatsqlv1.ReplicaLagPolicy) {
+	readiness := healthCheckCommand("readiness", group)
+	if group && lag != nil {
+		if lag.MaxTransactionsInQueue != nil {
+			readiness = append(readiness, "--max-transactions-in-queue="+strconv.FormatInt(*lag.MaxTransactionsInQueue, 10))
+		}
+		if lag.MaxSecondsBehind != nil {
+			readiness = append(readiness, "--max-seconds-behind="+strconv.FormatInt(*lag.MaxSecondsBehind, 10))
+		}
+	}
+
+	// readiness reacts fast to a member leaving the group
+	container.ReadinessProbe = newExecProbe(container.ReadinessProbe, readiness, 5, 3, 3)
+	// liveness only fails on a hung mysqld, a busy server gets one minute before it is restarted
+	container.LivenessProbe = newExecProbe(container.LivenessProbe, healthCheckCommand("liveness", false), 10, 5, 6)
+	// startup allows six hours for a clone or a crash recovery of a large data set
+	container.StartupProbe = newExecProbe(container.StartupProbe, healthCheckCommand("startup", false), 10, 5, 2160)
+}
+
+// healthCheckCommand returns the health check command of the probe
+func healthCheckCommand(probe string, group bool) []string {
+	command := []string{filepath.Join(consts.ToolsDir, consts.ToolsBinary), "healthcheck", probe}
+	if group {
+		command = append(command, "--group")
+	}
+	return command
+}
+
+// newExecProbe returns an exec probe running the command, the timing of the user probe is kept
+// and the defaults are used for the fields left empty
+func newExecProbe(user *corev1.Probe, command []string, period, timeout, failure int32) *corev1.Probe {
+	probe := &corev1.Probe{}
+	if user != nil {
+		probe = user.DeepCopy()
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = period
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = timeout
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = failure
+	}
+
+	// the check gives up one second before the kubelet does, so that a hung mysqld is reported as such
+	checkTimeout := probe.TimeoutSeconds - 1
+	if checkTimeout < 1 {
+		checkTimeout = 1
+	}
+	probe.ProbeHandler = corev1.ProbeHandler{
+		Exec: &corev1.ExecAction{
+			Command: append(command, "--timeout="+strconv.Itoa(int(checkTimeout))+"s"),
+		},
+	}
+	return probe
+}
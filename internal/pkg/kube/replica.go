@@ -0,0 +1,91 @@
+package kube
+
+import (
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 20:06:45
+ * @file: replica.go
+ * @description: statefulset of the source and the replicas of a ReplicaofGroupCluster
+ */
+
+// NewReplicaStatefulSet returns the statefulset of the ReplicaofGroupCluster, the members share one my.cnf,
+// the server_id and the read-only mode of each member are set when the replication is configured
+func NewReplicaStatefulSet(configMapName string, cr *greatsqlv1.ReplicaofGroupCluster) *appsv1.StatefulSet {
+	labels := map[string]string{
+		consts.AppKubernetesName:     cr.Name,
+		consts.AppKubernetesInstance: cr.Name,
+	}
+	size := cr.Spec.GetSize()
+
+	containers := NewContainers(cr.Name, &cr.Spec.PodSpec, StorageVolumeName(cr.Name, consts.Config, 0, false), 0, false)
+
+	statefulSet := &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "StatefulSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cr, schema.GroupVersionKind{
+					Group:   greatsqlv1.GroupVersion.Group,
+					Version: greatsqlv1.GroupVersion.Version,
+					Kind:    consts.ReplicaofGroupCluster,
+				}),
+			},
+			Labels: labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &size,
+			ServiceName: cr.Name + consts.HeadlessServiceSuffix,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers:                    containers,
+					TerminationGracePeriodSeconds: cr.Spec.PodSpec.TerminationGracePeriodSeconds,
+					SchedulerName:                 cr.Spec.PodSpec.SchedulerName,
+					Affinity:                      cr.PodAffinity(labels),
+					ServiceAccountName:            cr.Spec.PodSpec.ServiceAccountName,
+					SecurityContext:               cr.Spec.PodSpec.PodSecurityContext,
+					NodeSelector:                  cr.Spec.PodSpec.NodeSelector,
+					Tolerations:                   cr.Spec.PodSpec.Tolerations,
+					TopologySpreadConstraints:     cr.Spec.PodSpec.Affinity.SpreadConstraints(labels),
+					Volumes: append([]corev1.Volume{
+						{
+							Name: containers[0].VolumeMounts[0].Name,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: configMapName,
+									},
+									DefaultMode: &[]int32{0664}[0],
+								},
+							},
+						},
+					}, NewEmptyDirVolumes(cr.Name, &cr.Spec.PodSpec, 0, false)...),
+					DNSPolicy: cr.Spec.DnsPolicy,
+				},
+			},
+			VolumeClaimTemplates: NewVolumeClaimTemplates(cr.Name, &cr.Spec.PodSpec, labels, 0, false),
+			PodManagementPolicy:  appsv1.ParallelPodManagement,
+		},
+	}
+
+	InjectTools(&statefulSet.Spec.Template.Spec, &cr.Spec.PodSpec, cr.Name, false, cr.Spec.ReplicaLag)
+	MergePodSpec(&statefulSet.Spec.Template.Spec, &cr.Spec.PodSpec)
+	return statefulSet
+}
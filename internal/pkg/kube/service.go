@@ -1,11 +1,12 @@
 package kube
 
 import (
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
 	"github.com/gagraler/greatsql-operator/internal/consts"
-	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 /**
@@ -27,8 +28,8 @@ func NewService(name, nameSpace, kind string, objectMeta metav1.Object, port []c
 			Namespace: nameSpace,
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(objectMeta, schema.GroupVersionKind{
-					Group:   v1.SchemeGroupVersion.Group,
-					Version: v1.SchemeGroupVersion.Version,
+					Group:   greatsqlv1.GroupVersion.Group,
+					Version: greatsqlv1.GroupVersion.Version,
 					Kind:    kind,
 				}),
 			},
@@ -45,3 +46,35 @@ func NewService(name, nameSpace, kind string, objectMeta metav1.Object, port []c
 		},
 	}
 }
+
+// NewRoleService returns a service which selects the members of the instance with the given role label
+func NewRoleService(name, nameSpace, kind, instance, role string, objectMeta metav1.Object, port []corev1.ServicePort, svcType corev1.ServiceType) *corev1.Service {
+	service := NewService(name, nameSpace, kind, objectMeta, port, svcType)
+	service.Labels[consts.AppKubernetesInstance] = instance
+	service.Labels[consts.RoleLabel] = role
+	service.Spec.Selector = map[string]string{
+		consts.AppKubernetesInstance: instance,
+		consts.RoleLabel:             role,
+	}
+	return service
+}
+
+// NewMySQLServicePorts returns the client ports of the given service ports,
+// defaults to the mysql port when none is named mysql
+func NewMySQLServicePorts(ports []corev1.ServicePort) []corev1.ServicePort {
+	var mysqlPorts []corev1.ServicePort
+	for _, port := range ports {
+		if port.Name == consts.MysqlPortName {
+			mysqlPorts = append(mysqlPorts, port)
+		}
+	}
+	if len(mysqlPorts) == 0 {
+		mysqlPorts = append(mysqlPorts, corev1.ServicePort{
+			Name:       consts.MysqlPortName,
+			Port:       consts.MysqlPort,
+			TargetPort: intstr.FromInt32(consts.MysqlPort),
+			Protocol:   corev1.ProtocolTCP,
+		})
+	}
+	return mysqlPorts
+}
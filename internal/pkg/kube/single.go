@@ -6,7 +6,6 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -14,29 +13,30 @@ import (
 /**
  * @author: HuaiAn xu
  * @date: 2024-03-18 18:02:46
- * @file: deployment.go
- * @description: kubernetes deployment operation
+ * @file: single.go
+ * @description: statefulset of the SingleInstance
  */
 
-// NewDeployment returns a new deployment
-func NewDeployment(configMapName string, cr *greatsqlv1.SingleInstance, ordinal int) *appsv1.Deployment {
+// NewSingleInstanceStatefulSet returns the statefulset of the SingleInstance. It runs one pod with a stable name
+// which mounts the PersistentVolumeClaims of the instance, the pod is stopped before it is recreated on an update
+// so that two mysqld never run on the same data directory
+func NewSingleInstanceStatefulSet(configMapName string, cr *greatsqlv1.SingleInstance) *appsv1.StatefulSet {
 	labels := map[string]string{
 		consts.AppKubernetesName:     cr.Name,
 		consts.AppKubernetesInstance: cr.Name,
 	}
 	selector := &metav1.LabelSelector{MatchLabels: labels}
-	var affinity *corev1.Affinity
+	affinity := cr.PodAffinity(labels)
 
-	if cr.Spec.PodSpec.Affinity == nil {
-		cr.Spec.PodSpec.Affinity = nil
-	} else {
-		affinity = cr.PodAffinity(labels)
+	var replicas int32 = 1
+	if cr.Spec.Hibernate {
+		replicas = 0
 	}
 
-	return &appsv1.Deployment{
+	statefulSet := &appsv1.StatefulSet{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps/v1",
-			Kind:       "Deployment",
+			Kind:       "StatefulSet",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
@@ -50,14 +50,15 @@ func NewDeployment(configMapName string, cr *greatsqlv1.SingleInstance, ordinal
 			},
 			Labels: labels,
 		},
-		Spec: appsv1.DeploymentSpec{
-			Replicas: cr.Spec.Size,
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: cr.Name + consts.HeadlessServiceSuffix,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
-					Containers:                    NewContainers(cr.Name, &cr.Spec.PodSpec, ordinal, false),
+					Containers:                    NewContainers(cr.Name, &cr.Spec.PodSpec, StorageVolumeName(cr.Name, consts.Config, 0, false), 0, false),
 					TerminationGracePeriodSeconds: cr.Spec.PodSpec.TerminationGracePeriodSeconds,
 					SchedulerName:                 cr.Spec.PodSpec.SchedulerName,
 					Affinity:                      affinity,
@@ -65,9 +66,10 @@ func NewDeployment(configMapName string, cr *greatsqlv1.SingleInstance, ordinal
 					SecurityContext:               cr.Spec.PodSpec.PodSecurityContext,
 					NodeSelector:                  cr.Spec.PodSpec.NodeSelector,
 					Tolerations:                   cr.Spec.PodSpec.Tolerations,
-					Volumes: []corev1.Volume{
+					TopologySpreadConstraints:     cr.Spec.PodSpec.Affinity.SpreadConstraints(labels),
+					Volumes: append([]corev1.Volume{
 						{
-							Name: cr.Name + consts.Config,
+							Name: StorageVolumeName(cr.Name, consts.Config, 0, false),
 							VolumeSource: corev1.VolumeSource{
 								ConfigMap: &corev1.ConfigMapVolumeSource{
 									LocalObjectReference: corev1.LocalObjectReference{
@@ -77,26 +79,20 @@ func NewDeployment(configMapName string, cr *greatsqlv1.SingleInstance, ordinal
 								},
 							},
 						},
-						{
-							Name: cr.Name + consts.DB,
-							VolumeSource: corev1.VolumeSource{
-								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-									ClaimName: cr.Name + consts.DB,
-								},
-							},
-						},
-					},
+					}, NewClaimVolumes(cr.Name, &cr.Spec.PodSpec)...),
 					DNSPolicy: cr.Spec.DnsPolicy,
 				},
 			},
 			Selector: selector,
-			Strategy: appsv1.DeploymentStrategy{
-				Type: cr.Spec.UpdateStrategy,
-				RollingUpdate: &appsv1.RollingUpdateDeployment{
-					MaxUnavailable: &intstr.IntOrString{IntVal: 1},
-					MaxSurge:       &intstr.IntOrString{IntVal: 1},
-				},
+			// the single pod is deleted before its replacement is created
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
 			},
+			PodManagementPolicy: appsv1.OrderedReadyPodManagement,
 		},
 	}
+
+	InjectTools(&statefulSet.Spec.Template.Spec, &cr.Spec.PodSpec, cr.Name, false, nil)
+	MergePodSpec(&statefulSet.Spec.Template.Spec, &cr.Spec.PodSpec)
+	return statefulSet
 }
@@ -0,0 +1,75 @@
+package kube
+
+import (
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 17:48:21
+ * @file: snapshot.go
+ * @description: csi volume snapshot operation, the snapshot api is not part of client-go so unstructured is used
+ */
+
+// VolumeSnapshotGVK is the kind of the csi volume snapshot
+var VolumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
+// FinalSnapshotName returns the name of the final snapshot of the PersistentVolumeClaim, it holds the uid of the claim
+// so that the snapshot left by an earlier claim with the same name is never taken for the snapshot of the current one
+func FinalSnapshotName(pvcName string, pvcUID types.UID) string {
+	uid := string(pvcUID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return pvcName + consts.FinalSnapshotSuffix + "-" + uid
+}
+
+// NewVolumeSnapshot returns a volume snapshot of the PersistentVolumeClaim, it has no owner
+// so that it outlives the instance
+func NewVolumeSnapshot(name, namespace, pvcName string, pvcUID types.UID, className *string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvcName,
+		},
+	}
+	if className != nil && *className != "" {
+		spec["volumeSnapshotClassName"] = *className
+	}
+
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	snapshot.SetName(name)
+	snapshot.SetNamespace(namespace)
+	snapshot.SetLabels(map[string]string{
+		consts.AppKubernetesComponent: "final-snapshot",
+		consts.SourceClaimUIDLabel:    string(pvcUID),
+	})
+	return snapshot
+}
+
+// NewEmptyVolumeSnapshot returns an empty volume snapshot to get an existing one
+func NewEmptyVolumeSnapshot() *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	return snapshot
+}
+
+// VolumeSnapshotStatus returns whether the snapshot is ready to use and the error reported by the snapshotter
+func VolumeSnapshotStatus(snapshot *unstructured.Unstructured) (bool, string) {
+	ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	message, _, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message")
+	return ready, message
+}
+
+// IsSnapshotOf returns true if the snapshot was taken of the PersistentVolumeClaim with the uid
+func IsSnapshotOf(snapshot *unstructured.Unstructured, pvcName string, pvcUID types.UID) bool {
+	source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	return source == pvcName && snapshot.GetLabels()[consts.SourceClaimUIDLabel] == string(pvcUID)
+}
@@ -1,8 +1,6 @@
 package kube
 
 import (
-	"strconv"
-
 	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
 	"github.com/gagraler/greatsql-operator/internal/consts"
 	appsv1 "k8s.io/api/apps/v1"
@@ -18,7 +16,14 @@ import (
  * @description: statefulset operation
  */
 
-func NewStatefulSet(configMapName, serviceName string, cr *greatsqlv1.GroupReplicationCluster, ordinal int) *appsv1.StatefulSet {
+// GroupVolumeOrdinal suffixes the names of the storage volumes and claim templates of the group members, the claim
+// templates of a statefulset are immutable so the members keep the claims named after the former first ordinal
+const GroupVolumeOrdinal = 1
+
+// NewStatefulSet returns the statefulset of the GroupReplicationCluster, its members share the config template of
+// the configMap, my.cnf of each member is rendered from it by an init container with the server_id of its ordinal
+func NewStatefulSet(configMapName, serviceName string, cr *greatsqlv1.GroupReplicationCluster, serverIDBase uint32) *appsv1.StatefulSet {
+	const ordinal = GroupVolumeOrdinal
 
 	labels := map[string]string{
 		consts.AppKubernetesName:     cr.Name,
@@ -26,7 +31,12 @@ func NewStatefulSet(configMapName, serviceName string, cr *greatsqlv1.GroupRepli
 	}
 	affinity := cr.PodAffinity(labels)
 
-	return &appsv1.StatefulSet{
+	replicas := cr.Spec.Member[0].Size
+	if cr.Spec.Hibernate {
+		replicas = &[]int32{0}[0]
+	}
+
+	statefulSet := &appsv1.StatefulSet{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps/v1",
 			Kind:       "StatefulSet",
@@ -44,20 +54,20 @@ func NewStatefulSet(configMapName, serviceName string, cr *greatsqlv1.GroupRepli
 			Labels: labels,
 		},
 		Spec: appsv1.StatefulSetSpec{
-			Replicas:    cr.Spec.Member[0].Size,
+			Replicas:    replicas,
 			ServiceName: serviceName,
+			// a member is only ready once it is ONLINE in the group, the members are started together so that they can join it
+			PodManagementPolicy: appsv1.ParallelPodManagement,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						consts.AppKubernetesName: cr.Name,
-					},
+					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
-					// InitContainers:                NewInitContainers(cr.Name, cr.Spec.ClusterSpec.PodSpec, cr.Spec.ClusterSpec.Ports),
-					Containers:                    NewContainers(cr.Name, cr.Spec.ClusterSpec.PodSpec, ordinal, true),
+					InitContainers:                NewInitContainers(cr.Name, cr.Spec.ClusterSpec.PodSpec, serverIDBase),
+					Containers:                    NewContainers(cr.Name, cr.Spec.ClusterSpec.PodSpec, RenderedConfigVolumeName(cr.Name), ordinal, true),
 					TerminationGracePeriodSeconds: cr.Spec.ClusterSpec.PodSpec.TerminationGracePeriodSeconds,
 					SchedulerName:                 cr.Spec.ClusterSpec.PodSpec.SchedulerName,
 					Affinity:                      affinity,
@@ -65,9 +75,16 @@ func NewStatefulSet(configMapName, serviceName string, cr *greatsqlv1.GroupRepli
 					SecurityContext:               cr.Spec.ClusterSpec.PodSpec.PodSecurityContext,
 					NodeSelector:                  cr.Spec.ClusterSpec.PodSpec.NodeSelector,
 					Tolerations:                   cr.Spec.ClusterSpec.PodSpec.Tolerations,
-					Volumes: []corev1.Volume{
+					TopologySpreadConstraints:     cr.Spec.ClusterSpec.PodSpec.Affinity.SpreadConstraints(labels),
+					Volumes: append([]corev1.Volume{
+						{
+							Name: RenderedConfigVolumeName(cr.Name),
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
 						{
-							Name: cr.Name + consts.Config,
+							Name: ConfigTemplateVolumeName(cr.Name),
 							VolumeSource: corev1.VolumeSource{
 								ConfigMap: &corev1.ConfigMapVolumeSource{
 									LocalObjectReference: corev1.LocalObjectReference{
@@ -77,33 +94,11 @@ func NewStatefulSet(configMapName, serviceName string, cr *greatsqlv1.GroupRepli
 								},
 							},
 						},
-						{
-							//Name: cr.Name + consts.DB,
-							// VolumeSource: corev1.VolumeSource{
-							// 	PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-							// 		ClaimName: cr.Name + consts.DB + strconv.Itoa(ordinal),
-							// 	},
-							// },
-						},
-					},
+					}, NewEmptyDirVolumes(cr.Name, cr.Spec.ClusterSpec.PodSpec, ordinal, true)...),
 					DNSPolicy: cr.Spec.ClusterSpec.DnsPolicy,
 				},
 			},
-			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
-				{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:   cr.Name + consts.DB + strconv.Itoa(ordinal),
-						Labels: labels,
-					},
-					Spec: corev1.PersistentVolumeClaimSpec{
-						AccessModes: []corev1.PersistentVolumeAccessMode{
-							corev1.ReadWriteOnce,
-						},
-						Resources:        cr.Spec.ClusterSpec.PodSpec.PersistentVolumeClaimTemplate.Resources,
-						StorageClassName: cr.Spec.ClusterSpec.PodSpec.PersistentVolumeClaimTemplate.StorageClassName,
-					},
-				},
-			},
+			VolumeClaimTemplates: NewVolumeClaimTemplates(cr.Name, cr.Spec.ClusterSpec.PodSpec, labels, ordinal, true),
 			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
 				Type: cr.Spec.ClusterSpec.UpdateStrategy.Type,
 				// Type: appsv1.RollingUpdateStatefulSetStrategyType,
@@ -114,4 +109,8 @@ func NewStatefulSet(configMapName, serviceName string, cr *greatsqlv1.GroupRepli
 			},
 		},
 	}
+
+	InjectTools(&statefulSet.Spec.Template.Spec, cr.Spec.ClusterSpec.PodSpec, cr.Name, true, cr.Spec.ClusterSpec.ReplicaLag)
+	MergePodSpec(&statefulSet.Spec.Template.Spec, cr.Spec.ClusterSpec.PodSpec)
+	return statefulSet
 }
@@ -0,0 +1,93 @@
+package kube
+
+import (
+	"os"
+	"path/filepath"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	corev1 "k8s.io/api/core/v1"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 16:24:40
+ * @file: tools.go
+ * @description: operator tools shared with the greatsql pod
+ */
+
+// OperatorImage returns the image of the tools init container
+func OperatorImage() string {
+	if image := os.Getenv(consts.OperatorImageEnv); image != "" {
+		return image
+	}
+	return consts.DefaultOperatorImage
+}
+
+// NewToolsVolume returns the volume shared by the tools init container and the greatsql containers
+func NewToolsVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: consts.ToolsVolume,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+// NewToolsVolumeMount returns the volume mount of the tools volume
+func NewToolsVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      consts.ToolsVolume,
+		MountPath: consts.ToolsDir,
+	}
+}
+
+// NewToolsInitContainer returns the init container which installs the operator binary into the tools volume
+func NewToolsInitContainer(podSpec *greatsqlv1.PodSpec) corev1.Container {
+	container := corev1.Container{
+		Name:         consts.ToolsVolume,
+		Image:        OperatorImage(),
+		Command:      []string{"/" + consts.ToolsBinary, "install-tools", consts.ToolsDir},
+		VolumeMounts: []corev1.VolumeMount{NewToolsVolumeMount()},
+	}
+	if len(podSpec.Containers) > 0 {
+		container.ImagePullPolicy = podSpec.Containers[0].ImagePullPolicy
+	}
+	return container
+}
+
+// NewDataDirInitContainer returns the init container which initializes the data dir before mysqld is started,
+// it runs the operator binary of the tools volume in the greatsql image with the env and the volumes of the container
+func NewDataDirInitContainer(container corev1.Container) corev1.Container {
+	return corev1.Container{
+		Name:                     consts.DataDirInitContainer,
+		Image:                    container.Image,
+		ImagePullPolicy:          container.ImagePullPolicy,
+		Command:                  []string{filepath.Join(consts.ToolsDir, consts.ToolsBinary), "init-datadir"},
+		Env:                      container.Env,
+		EnvFrom:                  container.EnvFrom,
+		Resources:                container.Resources,
+		SecurityContext:          container.SecurityContext,
+		VolumeMounts:             append([]corev1.VolumeMount(nil), container.VolumeMounts...),
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+}
+
+// InjectTools installs the operator tools into the pod: the tools init container and volume, the data dir init container,
+// the built-in probes of the greatsql container unless they are disabled, the preStop hook and the agent sidecar
+func InjectTools(pod *corev1.PodSpec, podSpec *greatsqlv1.PodSpec, name string, group bool, lag *greatsqlv1.ReplicaLagPolicy) {
+	if len(pod.Containers) == 0 {
+		return
+	}
+	pod.InitContainers = append(pod.InitContainers, NewToolsInitContainer(podSpec))
+	pod.Volumes = append(pod.Volumes, NewToolsVolume())
+
+	container := &pod.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, NewToolsVolumeMount())
+	pod.InitContainers = append(pod.InitContainers, NewDataDirInitContainer(*container))
+	if BuiltinProbesEnabled(podSpec) {
+		SetBuiltinProbes(container, group, lag)
+	}
+	SetPreStopHook(container, group, podSpec.TerminationGracePeriodSeconds)
+	pod.Containers = append(pod.Containers, NewAgentContainer(*container, name, group))
+}
@@ -1,8 +1,11 @@
 package kube
 
 import (
+	"fmt"
+
 	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
 	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,33 +18,221 @@ import (
  * @description: persistent volume
  */
 
-// NewPersistentVolumeClaim returns a new persistent volume claim
-func NewPersistentVolumeClaim(name, namespace string, cr *greatsqlv1.PodSpec) *corev1.PersistentVolumeClaim {
+// states of a volume of a member
+const (
+	VolumeReady                   = "Ready"
+	VolumeExpanding               = "Expanding"
+	VolumeFileSystemResizePending = "FileSystemResizePending"
+	VolumeExpansionUnsupported    = "ExpansionUnsupported"
+)
+
+// PersistentVolumeClaimName returns the name of the data PersistentVolumeClaim of the instance
+func PersistentVolumeClaimName(name string) string {
+	return name + consts.DB
+}
+
+// StorageClaimName returns the name of the PersistentVolumeClaim of the volume of the instance,
+// the data claim keeps its original name so that the existing instances reuse it
+func StorageClaimName(name, volume string) string {
+	if volume == consts.DB {
+		return PersistentVolumeClaimName(name)
+	}
+	return fmt.Sprintf("%s-%s", name, volume)
+}
 
-	defaultStorage := *setDefaultStorage(cr)
-	storageQuantity := defaultStorage.String()
+// storageVolume is a volume of the storage layout of the instance
+type storageVolume struct {
+	name      string
+	mountPath string
+	spec      greatsqlv1.VolumeSpec
+}
+
+// storageVolumes returns the volumes of the storage layout, the data volume first
+func storageVolumes(cr *greatsqlv1.PodSpec) []storageVolume {
+	data := greatsqlv1.VolumeSpec{Size: *setDefaultStorage(cr)}
+	if cr.PersistentVolumeClaimTemplate != nil {
+		data.StorageClassName = cr.PersistentVolumeClaimTemplate.StorageClassName
+	}
+	storage := cr.Storage
+	if storage == nil {
+		storage = &greatsqlv1.Storage{}
+	}
+	if storage.Data != nil {
+		data.StorageClassName = storage.Data.StorageClassName
+		if !storage.Data.Size.IsZero() {
+			data.Size = storage.Data.Size
+		}
+	}
+
+	volumes := []storageVolume{{name: consts.DB, mountPath: consts.DataDir, spec: data}}
+	for _, volume := range []struct {
+		name, mountPath string
+		spec            *greatsqlv1.VolumeSpec
+	}{
+		{consts.Binlog, consts.BinlogDir, storage.Binlog},
+		{consts.Redo, consts.RedoDir, storage.Redo},
+		{consts.Undo, consts.UndoDir, storage.Undo},
+		{consts.Tmp, consts.TmpDir, storage.Tmp},
+	} {
+		if volume.spec == nil {
+			continue
+		}
+		spec := *volume.spec
+		if spec.Size.IsZero() {
+			spec.Size = data.Size
+		}
+		volumes = append(volumes, storageVolume{name: volume.name, mountPath: volume.mountPath, spec: spec})
+	}
+	return volumes
+}
 
+// StorageVolumeName returns the name of the volume in the pod, suffixed with the ordinal for the statefulset members
+func StorageVolumeName(name, volume string, ordinal int, isStatefulSet bool) string {
+	if isStatefulSet {
+		return fmt.Sprintf("%s-%s-%d", name, volume, ordinal)
+	}
+	return fmt.Sprintf("%s-%s", name, volume)
+}
+
+// StorageLayout returns the directories of my.cnf for the storage layout, the files without a volume stay in the data directory
+func StorageLayout(cr *greatsqlv1.PodSpec) mysql.StorageLayout {
+	var layout mysql.StorageLayout
+	for _, volume := range storageVolumes(cr) {
+		switch volume.name {
+		case consts.Binlog:
+			layout.BinlogDir = volume.mountPath
+		case consts.Redo:
+			layout.RedoDir = volume.mountPath
+		case consts.Undo:
+			layout.UndoDir = volume.mountPath
+		case consts.Tmp:
+			layout.TmpDir = volume.mountPath
+		}
+	}
+	return layout
+}
+
+// StorageVolumeMountPaths returns the volumes of the storage layout keyed by their mount path
+func StorageVolumeMountPaths(cr *greatsqlv1.PodSpec) map[string]string {
+	paths := make(map[string]string)
+	for _, volume := range storageVolumes(cr) {
+		paths[volume.mountPath] = volume.name
+	}
+	return paths
+}
+
+// NewStorageVolumeMounts returns the volume mounts of the storage layout
+func NewStorageVolumeMounts(name string, cr *greatsqlv1.PodSpec, ordinal int, isStatefulSet bool) []corev1.VolumeMount {
+	var mounts []corev1.VolumeMount
+	for _, volume := range storageVolumes(cr) {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      StorageVolumeName(name, volume.name, ordinal, isStatefulSet),
+			MountPath: volume.mountPath,
+		})
+	}
+	return mounts
+}
+
+// NewEmptyDirVolumes returns the volumes of the storage layout backed by an emptyDir
+func NewEmptyDirVolumes(name string, cr *greatsqlv1.PodSpec, ordinal int, isStatefulSet bool) []corev1.Volume {
+	var volumes []corev1.Volume
+	for _, volume := range storageVolumes(cr) {
+		if volume.spec.EmptyDir == nil {
+			continue
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name:         StorageVolumeName(name, volume.name, ordinal, isStatefulSet),
+			VolumeSource: corev1.VolumeSource{EmptyDir: volume.spec.EmptyDir},
+		})
+	}
+	return volumes
+}
+
+// NewClaimVolumes returns the volumes of the storage layout of a deployment, backed by the PersistentVolumeClaims
+// of NewPersistentVolumeClaims or by an emptyDir
+func NewClaimVolumes(name string, cr *greatsqlv1.PodSpec) []corev1.Volume {
+	volumes := NewEmptyDirVolumes(name, cr, 0, false)
+	for _, volume := range storageVolumes(cr) {
+		if volume.spec.EmptyDir != nil {
+			continue
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: StorageVolumeName(name, volume.name, 0, false),
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: StorageClaimName(name, volume.name),
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// NewVolumeClaimTemplates returns the volume claim templates of the storage layout of a statefulset
+func NewVolumeClaimTemplates(name string, cr *greatsqlv1.PodSpec, labels map[string]string, ordinal int, isStatefulSet bool) []corev1.PersistentVolumeClaim {
+	var templates []corev1.PersistentVolumeClaim
+	for _, volume := range storageVolumes(cr) {
+		if volume.spec.EmptyDir != nil {
+			continue
+		}
+		templates = append(templates, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   StorageVolumeName(name, volume.name, ordinal, isStatefulSet),
+				Labels: labels,
+			},
+			Spec: newPersistentVolumeClaimSpec(volume.spec),
+		})
+	}
+	return templates
+}
+
+// NewPersistentVolumeClaims returns the PersistentVolumeClaims of the storage layout of a deployment
+func NewPersistentVolumeClaims(name, namespace string, cr *greatsqlv1.PodSpec) []*corev1.PersistentVolumeClaim {
+	var pvcs []*corev1.PersistentVolumeClaim
+	for _, volume := range storageVolumes(cr) {
+		if volume.spec.EmptyDir != nil {
+			continue
+		}
+		pvc := NewPersistentVolumeClaim(name, namespace, cr)
+		pvc.Name = StorageClaimName(name, volume.name)
+		pvc.Spec = newPersistentVolumeClaimSpec(volume.spec)
+		pvcs = append(pvcs, pvc)
+	}
+	return pvcs
+}
+
+// NewPersistentVolumeClaim returns a new persistent volume claim
+func NewPersistentVolumeClaim(name, namespace string, cr *greatsqlv1.PodSpec) *corev1.PersistentVolumeClaim {
 	return &corev1.PersistentVolumeClaim{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: corev1.SchemeGroupVersion.String(),
 			Kind:       "PersistentVolumeClaim",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name + consts.DB,
+			Name:      PersistentVolumeClaimName(name),
 			Namespace: namespace,
-		},
-		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes: []corev1.PersistentVolumeAccessMode{
-				corev1.ReadWriteOnce,
+			Labels: map[string]string{
+				consts.AppKubernetesName:     name,
+				consts.AppKubernetesInstance: name,
 			},
-			Resources: corev1.VolumeResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceStorage: resource.MustParse(storageQuantity),
-				},
+		},
+		Spec: newPersistentVolumeClaimSpec(storageVolumes(cr)[0].spec),
+	}
+}
+
+// newPersistentVolumeClaimSpec returns the spec of the PersistentVolumeClaim of the volume
+func newPersistentVolumeClaimSpec(volume greatsqlv1.VolumeSpec) corev1.PersistentVolumeClaimSpec {
+	return corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{
+			corev1.ReadWriteOnce,
+		},
+		Resources: corev1.VolumeResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: volume.Size,
 			},
-			StorageClassName: cr.PersistentVolumeClaimTemplate.StorageClassName,
-			VolumeMode:       setDefaultPersistentVolumeMode(),
 		},
+		StorageClassName: volume.StorageClassName,
+		VolumeMode:       setDefaultPersistentVolumeMode(),
 	}
 }
 
@@ -59,3 +250,45 @@ func setDefaultPersistentVolumeMode() *corev1.PersistentVolumeMode {
 	mode := corev1.PersistentVolumeFilesystem
 	return &mode
 }
+
+// AutoGrowSize returns the size a claim requesting the size grows to, one step capped by the maximum size
+// of the auto-grow policy, false if the claim has reached the maximum size or the step does not grow it
+func AutoGrowSize(requested resource.Quantity, policy *greatsqlv1.StorageAutoGrow) (resource.Quantity, bool) {
+	if requested.Cmp(policy.MaxSize) >= 0 || policy.Step.Sign() <= 0 {
+		return requested, false
+	}
+	next := requested.DeepCopy()
+	next.Add(policy.Step)
+	if next.Cmp(policy.MaxSize) > 0 {
+		next = policy.MaxSize.DeepCopy()
+	}
+	return next, true
+}
+
+// NeedsExpansion returns true if the claim requests less than the wanted size, a claim is never shrunk
+func NeedsExpansion(pvc *corev1.PersistentVolumeClaim, want resource.Quantity) bool {
+	return pvc.Spec.Resources.Requests.Storage().Cmp(want) < 0
+}
+
+// VolumeStatus returns the state of the PersistentVolumeClaim expanded to the wanted size
+func VolumeStatus(pvc *corev1.PersistentVolumeClaim, want resource.Quantity) greatsqlv1.VolumeStatus {
+	status := greatsqlv1.VolumeStatus{
+		Name:      pvc.Name,
+		Capacity:  pvc.Status.Capacity.Storage().String(),
+		Requested: want.String(),
+		State:     VolumeReady,
+	}
+	if pvc.Status.Capacity.Storage().Cmp(want) >= 0 {
+		return status
+	}
+	status.State = VolumeExpanding
+	if NeedsExpansion(pvc, want) {
+		status.State = VolumeExpansionUnsupported
+	}
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending && condition.Status == corev1.ConditionTrue {
+			status.State = VolumeFileSystemResizePending
+		}
+	}
+	return status
+}
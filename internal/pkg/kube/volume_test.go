@@ -0,0 +1,84 @@
+package kube
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-20 09:12:40
+ * @file: volume_test.go
+ * @description: persistent volume test
+ */
+
+func TestAutoGrowSize(t *testing.T) {
+	policy := func(step, maxSize string) *greatsqlv1.StorageAutoGrow {
+		return &greatsqlv1.StorageAutoGrow{Step: resource.MustParse(step), MaxSize: resource.MustParse(maxSize)}
+	}
+
+	tests := []struct {
+		name      string
+		requested string
+		policy    *greatsqlv1.StorageAutoGrow
+		want      string
+		grow      bool
+	}{
+		{name: "one step", requested: "10Gi", policy: policy("5Gi", "50Gi"), want: "15Gi", grow: true},
+		{name: "capped by the maximum size", requested: "48Gi", policy: policy("5Gi", "50Gi"), want: "50Gi", grow: true},
+		{name: "at the maximum size", requested: "50Gi", policy: policy("5Gi", "50Gi"), want: "50Gi", grow: false},
+		{name: "above the maximum size", requested: "60Gi", policy: policy("5Gi", "50Gi"), want: "60Gi", grow: false},
+		{name: "zero step", requested: "10Gi", policy: policy("0", "50Gi"), want: "10Gi", grow: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, grow := AutoGrowSize(resource.MustParse(tt.requested), tt.policy)
+			if grow != tt.grow || got.Cmp(resource.MustParse(tt.want)) != 0 {
+				t.Errorf("AutoGrowSize() = %s, %v, want %s, %v", got.String(), grow, tt.want, tt.grow)
+			}
+		})
+	}
+}
+
+func TestVolumeStatus(t *testing.T) {
+	claim := func(requested, capacity string, conditions ...corev1.PersistentVolumeClaimConditionType) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvc.Name = "data-mgr-0"
+		pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(requested)}
+		pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+		for _, condition := range conditions {
+			pvc.Status.Conditions = append(pvc.Status.Conditions, corev1.PersistentVolumeClaimCondition{Type: condition, Status: corev1.ConditionTrue})
+		}
+		return pvc
+	}
+
+	tests := []struct {
+		name   string
+		pvc    *corev1.PersistentVolumeClaim
+		want   string
+		state  string
+		expand bool
+	}{
+		{name: "ready", pvc: claim("10Gi", "10Gi"), want: "10Gi", state: VolumeReady},
+		{name: "never shrunk", pvc: claim("20Gi", "20Gi"), want: "10Gi", state: VolumeReady},
+		{name: "expanding", pvc: claim("20Gi", "10Gi"), want: "20Gi", state: VolumeExpanding},
+		{name: "file system resize pending", pvc: claim("20Gi", "10Gi", corev1.PersistentVolumeClaimFileSystemResizePending),
+			want: "20Gi", state: VolumeFileSystemResizePending},
+		{name: "expansion unsupported", pvc: claim("10Gi", "10Gi"), want: "20Gi", state: VolumeExpansionUnsupported, expand: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := resource.MustParse(tt.want)
+			if got := VolumeStatus(tt.pvc, want); got.State != tt.state || got.Requested != tt.want {
+				t.Errorf("VolumeStatus() = %+v, want state %s", got, tt.state)
+			}
+			if got := NeedsExpansion(tt.pvc, want); got != tt.expand {
+				t.Errorf("NeedsExpansion() = %v, want %v", got, tt.expand)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package lifecycle
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/health"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 16:52:30
+ * @file: prestop.go
+ * @description: preStop hook of the greatsql container, leaves the group before mysqld stops
+ */
+
+var (
+	port    int32
+	group   bool
+	timeout time.Duration
+)
+
+var PreStopCmd = &cobra.Command{
+	Use:   "prestop",
+	Short: "Hand over the primary role, leave the group and shut down the local GreatSQL member",
+	Run: func(cmd *cobra.Command, args []string) {
+		// the hook never fails the termination, the kubelet kills mysqld once the grace period is over
+		if err := PreStop(health.LocalClient(port, timeout), group, timeout); err != nil {
+			fmt.Fprintf(os.Stderr, "prestop error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	PreStopCmd.Flags().Int32Var(&port, "port", consts.MysqlPort, "The port of the local mysqld.")
+	PreStopCmd.Flags().BoolVar(&group, "group", false, "The member is part of a group replication.")
+	PreStopCmd.Flags().DurationVar(&timeout, "timeout", 25*time.Second, "The time the member has to stop.")
+}
+
+// PreStop hands over the primary role to an ONLINE secondary, leaves the group and shuts down mysqld,
+// each step is skipped once the deadline has passed so that the shutdown still happens in time
+func PreStop(client *mysql.MySQL, group bool, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	if group {
+		if err := leaveGroup(client, deadline); err != nil {
+			fmt.Fprintf(os.Stderr, "prestop could not leave the group cleanly: %v\n", err)
+		}
+	}
+	return client.Shutdown()
+}
+
+// leaveGroup switches the primary role over if the member is the primary, then stops the group replication
+func leaveGroup(client *mysql.MySQL, deadline time.Time) error {
+	self, err := client.GetServerUUID()
+	if err != nil {
+		return err
+	}
+	members, err := client.GetGroupMembers()
+	if err != nil {
+		return err
+	}
+
+	if isPrimary(members, self) {
+		weights, err := client.GetMemberWeights()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "prestop could not read the weight of the members, they are ordered by host: %v\n", err)
+		}
+		target, ok := handoverTarget(members, self, weights)
+		switch {
+		case !ok:
+			fmt.Println("prestop: no ONLINE secondary to take over the primary role")
+		case time.Now().After(deadline):
+			return fmt.Errorf("no time left for the switchover to %s", target.Host)
+		default:
+			fmt.Printf("prestop: switch the primary role over to %s\n", target.Host)
+			if err := client.SetAsPrimary(target.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if time.Now().After(deadline) {
+		return fmt.Errorf("no time left to stop the group replication")
+	}
+	return client.StopGroupReplication()
+}
+
+// isPrimary returns true if the member is the ONLINE primary of the group
+func isPrimary(members []mysql.GroupMember, self string) bool {
+	for _, member := range members {
+		if member.ID == self && member.IsPrimary() {
+			return true
+		}
+	}
+	return false
+}
+
+// handoverTarget returns the secondary the primary role is handed over to, the weights of the group keyed by
+// MEMBER_ID are applied to the members first. Without weights the secondaries are ordered by host
+func handoverTarget(members []mysql.GroupMember, self string, weights map[string]int32) (mysql.GroupMember, bool) {
+	weighted := make([]mysql.GroupMember, len(members))
+	for i, member := range members {
+		member.Weight = weights[member.ID]
+		weighted[i] = member
+	}
+	return mysql.SwitchoverTarget(weighted, self)
+}
@@ -0,0 +1,57 @@
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-20 03:02:51
+ * @file: prestop_test.go
+ * @description: preStop hook test
+ */
+
+func TestHandoverTarget(t *testing.T) {
+	members := []mysql.GroupMember{
+		{ID: "a", Host: "mgr-0", State: mysql.MemberStateOnline, Role: mysql.MemberRolePrimary},
+		{ID: "b", Host: "mgr-1", State: mysql.MemberStateOnline, Role: mysql.MemberRoleSecondary},
+		{ID: "c", Host: "mgr-2", State: mysql.MemberStateOnline, Role: mysql.MemberRoleSecondary},
+		{ID: "d", Host: "mgr-3", State: mysql.MemberStateRecovering, Role: mysql.MemberRoleSecondary},
+	}
+
+	tests := []struct {
+		name    string
+		members []mysql.GroupMember
+		self    string
+		weights map[string]int32
+		want    string
+		primary bool
+	}{
+		{name: "without weights the lowest host", members: members, self: "a", want: "b", primary: true},
+		{name: "the highest weight", members: members, self: "a", weights: map[string]int32{"a": 90, "b": 50, "c": 70}, want: "c", primary: true},
+		{name: "the lowest host of the highest weight", members: members, self: "a", weights: map[string]int32{"b": 70, "c": 70}, want: "b", primary: true},
+		{name: "a recovering member is never the target", members: members, self: "a", weights: map[string]int32{"d": 100}, want: "b", primary: true},
+		{name: "no secondary", members: members[:1], self: "a", primary: true},
+		{name: "secondary hands nothing over", members: members, self: "b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPrimary(tt.members, tt.self); got != tt.primary {
+				t.Fatalf("isPrimary() = %v, want %v", got, tt.primary)
+			}
+			if !tt.primary {
+				return
+			}
+			target, ok := handoverTarget(tt.members, tt.self, tt.weights)
+			if ok != (tt.want != "") || target.ID != tt.want {
+				t.Errorf("handoverTarget() = %s, %v, want %q", target.ID, ok, tt.want)
+			}
+		})
+	}
+
+	if members[1].Weight != 0 {
+		t.Error("handoverTarget() changes the weight of the members of the caller")
+	}
+}
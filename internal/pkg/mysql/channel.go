@@ -0,0 +1,145 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 21:14:36
+ * @file: channel.go
+ * @description: named replication channel of a group replicating from another group
+ */
+
+// DisasterRecoveryChannel is the channel of a disaster recovery group replicating from its source group
+const DisasterRecoveryChannel = "dr"
+
+// ConversionChannel is the channel of a group replicating from the SingleInstance converted into it
+const ConversionChannel = "conversion"
+
+// errChannelDoesNotExist is ER_REPLICA_CHANNEL_DOES_NOT_EXIST
+const errChannelDoesNotExist = 3074
+
+// ReplicationChannel is a named replication channel replicating from a source group,
+// the channel follows the primary of the source group with asynchronous connection failover
+type ReplicationChannel struct {
+	Name string `json:"name"`
+	// Host and Port are the writer endpoint of the source group, the first source of the channel
+	Host string `json:"host,omitempty"`
+	Port int32  `json:"port,omitempty"`
+	// GroupName is the group_replication_group_name of the source group, empty if the source is a single instance
+	// which the channel does not fail over from
+	GroupName string `json:"groupName"`
+}
+
+// GetChannelStatus returns the replication state of the channel, Replica is false if the channel does not exist
+func (m *MySQL) GetChannelStatus(channel string) (ReplicaStatus, error) {
+	status, err := m.getReplicaStatus(fmt.Sprintf("SHOW REPLICA STATUS FOR CHANNEL %s;", quote(channel)))
+	var mysqlErr *driver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errChannelDoesNotExist {
+		return status, nil
+	}
+	return status, err
+}
+
+// StartChannel points the channel to the source group with GTID auto position and starts it,
+// the members of the source group are managed as failover sources so that the channel follows its primary,
+// the connected member is made read-only, the applier of the channel is not affected by it.
+// For a source group the member action mysql_start_failover_channels_if_primary is enabled so that
+// the primary elected next starts the channel, which has to be configured on it with ConfigureChannel
+func (m *MySQL) StartChannel(channel ReplicationChannel, user, password string) error {
+	if err := m.ConfigureChannel(channel, user, password); err != nil {
+		return err
+	}
+
+	if channel.GroupName != "" {
+		// the managed source is replaced since its first member may have changed,
+		// the managed sources are shared with the other members of the group
+		_ = m.executeQuery("SELECT asynchronous_connection_failover_delete_managed(?, ?);", channel.Name, channel.GroupName)
+		if err := m.executeQuery("SELECT asynchronous_connection_failover_add_managed(?, 'GroupReplication', ?, ?, ?, '', 80, 60);",
+			channel.Name, channel.GroupName, channel.Host, channel.Port); err != nil {
+			return fmt.Errorf("could not add the managed source of channel %s: %v", channel.Name, err)
+		}
+		if err := m.executeQuery("SELECT group_replication_enable_member_action('mysql_start_failover_channels_if_primary', 'AFTER_PRIMARY_ELECTION');"); err != nil {
+			return fmt.Errorf("could not enable the failover channels of the primary: %v", err)
+		}
+	}
+
+	return m.executeStatements(
+		fmt.Sprintf("START REPLICA FOR CHANNEL %s;", quote(channel.Name)),
+		"SET GLOBAL super_read_only = ON;",
+	)
+}
+
+// ConfigureChannel points the channel to the source group with GTID auto position without starting it,
+// a secondary of the group keeps the channel configured so that it starts the channel once it is elected primary
+func (m *MySQL) ConfigureChannel(channel ReplicationChannel, user, password string) error {
+	status, err := m.GetChannelStatus(channel.Name)
+	if err != nil {
+		return err
+	}
+
+	var statements []string
+	if status.Replica {
+		statements = append(statements, fmt.Sprintf("STOP REPLICA FOR CHANNEL %s;", quote(channel.Name)))
+	}
+	failover := 0
+	if channel.GroupName != "" {
+		failover = 1
+	}
+	statements = append(statements,
+		fmt.Sprintf("CHANGE REPLICATION SOURCE TO SOURCE_HOST = %s, SOURCE_PORT = %d, SOURCE_USER = %s, SOURCE_PASSWORD = %s, "+
+			"SOURCE_AUTO_POSITION = 1, GET_SOURCE_PUBLIC_KEY = 1, SOURCE_CONNECT_RETRY = 10, SOURCE_RETRY_COUNT = 10, "+
+			"SOURCE_CONNECTION_AUTO_FAILOVER = %d FOR CHANNEL %s;",
+			quote(channel.Host), channel.Port, quote(user), quote(password), failover, quote(channel.Name)),
+	)
+	return m.executeStatements(statements...)
+}
+
+// RemoveChannel stops and removes the channel, the read-only state of the connected member is kept
+func (m *MySQL) RemoveChannel(name string) error {
+	status, err := m.GetChannelStatus(name)
+	if err != nil || !status.Replica {
+		return err
+	}
+	return m.executeStatements(
+		fmt.Sprintf("STOP REPLICA FOR CHANNEL %s;", quote(name)),
+		fmt.Sprintf("RESET REPLICA ALL FOR CHANNEL %s;", quote(name)),
+	)
+}
+
+// PromoteChannel stops the channel once the relay log already received is applied, removes it with its
+// managed source, and makes the connected member writable
+func (m *MySQL) PromoteChannel(channel ReplicationChannel) error {
+	status, err := m.GetChannelStatus(channel.Name)
+	if err != nil {
+		return err
+	}
+
+	if status.Replica {
+		query := fmt.Sprintf("SHOW REPLICA STATUS FOR CHANNEL %s;", quote(channel.Name))
+		if err := m.executeQuery(fmt.Sprintf("STOP REPLICA IO_THREAD FOR CHANNEL %s;", quote(channel.Name))); err != nil {
+			return err
+		}
+		if err := m.waitForRelayLog(query); err != nil {
+			return err
+		}
+		if err := m.executeStatements(
+			fmt.Sprintf("STOP REPLICA FOR CHANNEL %s;", quote(channel.Name)),
+			fmt.Sprintf("RESET REPLICA ALL FOR CHANNEL %s;", quote(channel.Name)),
+		); err != nil {
+			return err
+		}
+		if channel.GroupName != "" {
+			_ = m.executeQuery("SELECT asynchronous_connection_failover_delete_managed(?, ?);", channel.Name, channel.GroupName)
+		}
+	}
+
+	return m.executeStatements(
+		"SET GLOBAL super_read_only = OFF;",
+		"SET GLOBAL read_only = OFF;",
+	)
+}
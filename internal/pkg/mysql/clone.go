@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 15:05:44
+ * @file: clone.go
+ * @description: clone plugin, donor preparation, remote clone and progress
+ */
+
+// CloneStage is a stage of the clone operation, as reported by performance_schema.clone_progress
+type CloneStage struct {
+	Stage    string `json:"stage"`
+	State    string `json:"state"`
+	Estimate int64  `json:"estimate"`
+	Data     int64  `json:"data"`
+}
+
+// states of a clone operation
+const (
+	CloneStateInProgress = "In Progress"
+	CloneStateCompleted  = "Completed"
+	CloneStateFailed     = "Failed"
+)
+
+// cloneRestartStage is the last stage of a clone operation, mysqld restarts on the cloned data
+const cloneRestartStage = "RESTART"
+
+// errRestartServerFailed is ER_RESTART_SERVER_FAILED, returned once the data is cloned when mysqld
+// is not managed by a supervisor which restarts it
+const errRestartServerFailed = 3707
+
+// CloneDonor is the instance the data is cloned from
+type CloneDonor struct {
+	Host string `json:"host"`
+	Port int32  `json:"port"`
+}
+
+// CloneState returns the state of the clone operation from its stages, empty if the member has never been cloned.
+// It is completed once mysqld has recovered the cloned data, the restart stage fails without a supervisor
+func CloneState(stages []CloneStage) string {
+	if len(stages) == 0 {
+		return ""
+	}
+	for _, stage := range stages {
+		if stage.Stage == cloneRestartStage {
+			continue
+		}
+		switch stage.State {
+		case CloneStateFailed:
+			return CloneStateFailed
+		case CloneStateCompleted:
+		default:
+			return CloneStateInProgress
+		}
+	}
+	return CloneStateCompleted
+}
+
+// PrepareCloneDonor installs the clone plugin on the connected instance if needed, and creates the user
+// the recipient clones and replicates with
+func (m *MySQL) PrepareCloneDonor(username, password string) error {
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	var status string
+	err = db.QueryRow("SELECT PLUGIN_STATUS FROM information_schema.PLUGINS WHERE PLUGIN_NAME = 'clone';").Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err := db.Exec("INSTALL PLUGIN clone SONAME 'mysql_clone.so';"); err != nil {
+			return fmt.Errorf("could not install the clone plugin: %v", err)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if err := m.EnsureReplicationUser(username, password); err != nil {
+		return err
+	}
+	return m.executeQuery("GRANT BACKUP_ADMIN ON *.* TO ?@'%';", username)
+}
+
+// CloneInstance replaces the data of the connected member with the data of the donor, it returns once
+// the data is cloned. mysqld has no supervisor in the pod, it is shut down to restart on the cloned data
+func (m *MySQL) CloneInstance(donor CloneDonor, username, password string) error {
+	if err := m.executeStatements(
+		fmt.Sprintf("SET GLOBAL clone_valid_donor_list = %s;", quote(fmt.Sprintf("%s:%d", donor.Host, donor.Port))),
+	); err != nil {
+		return err
+	}
+	err := m.executeQuery(fmt.Sprintf("CLONE INSTANCE FROM %s@%s:%d IDENTIFIED BY %s;",
+		quote(username), quote(donor.Host), donor.Port, quote(password)))
+	var mysqlErr *driver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errRestartServerFailed {
+		return m.Shutdown()
+	}
+	return err
+}
+
+// GetCloneProgress returns the stages of the last clone operation of the connected member,
+// empty if the member has never been cloned
+func (m *MySQL) GetCloneProgress() ([]CloneStage, error) {
+	query := "SELECT STAGE, STATE, ESTIMATE, DATA FROM performance_schema.clone_progress ORDER BY ID;"
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stages []CloneStage
+	for rows.Next() {
+		var stage CloneStage
+		var estimate, data sql.NullInt64
+		if err := rows.Scan(&stage.Stage, &stage.State, &estimate, &data); err != nil {
+			return nil, err
+		}
+		stage.Estimate = estimate.Int64
+		stage.Data = data.Int64
+		stages = append(stages, stage)
+	}
+
+	return stages, rows.Err()
+}
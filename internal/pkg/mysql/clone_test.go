@@ -0,0 +1,41 @@
+package mysql
+
+import (
+	"testing"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 22:41:08
+ * @file: clone_test.go
+ * @description: clone operation state test
+ */
+
+func TestCloneState(t *testing.T) {
+	stages := func(states ...string) []CloneStage {
+		names := []string{"DROP DATA", "FILE COPY", "PAGE COPY", "REDO COPY", "FILE SYNC", "RESTART", "RECOVERY"}
+		var result []CloneStage
+		for i, state := range states {
+			result = append(result, CloneStage{Stage: names[i], State: state})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		stages []CloneStage
+		want   string
+	}{
+		{"never cloned", nil, ""},
+		{"copying", stages("Completed", "In Progress", "Not Started"), CloneStateInProgress},
+		{"failed", stages("Completed", "Failed", "Not Started"), CloneStateFailed},
+		{"restart failed", stages("Completed", "Completed", "Completed", "Completed", "Completed", "Failed", "Not Started"), CloneStateInProgress},
+		{"restarted by hand", stages("Completed", "Completed", "Completed", "Completed", "Completed", "Failed", "Completed"), CloneStateCompleted},
+		{"restarted", stages("Completed", "Completed", "Completed", "Completed", "Completed", "Completed", "Completed"), CloneStateCompleted},
+	}
+	for _, tt := range tests {
+		if got := CloneState(tt.stages); got != tt.want {
+			t.Errorf("%s: CloneState() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"hash/fnv"
 	"os"
+	"strconv"
+	"strings"
 	"text/template"
 )
 
@@ -27,6 +30,51 @@ type MySQLConfig struct {
 	ReportHost                   string
 	ReportPort                   int
 	InnodbBufferPoolSize         string
+	// EnableSemiSync loads the semi-sync plugins, they are enabled at runtime on the source and the replicas
+	EnableSemiSync bool
+	StorageLayout
+}
+
+// StorageLayout is the directories of the files stored out of the data directory, empty keeps the files in it
+type StorageLayout struct {
+	BinlogDir string
+	RedoDir   string
+	UndoDir   string
+	TmpDir    string
+}
+
+// placeholders of the config template shared by the members of a group, replaced by each member when its pod starts
+const (
+	// ServerIDPlaceholder is replaced by the server_id of the member
+	ServerIDPlaceholder = "${SERVER_ID}"
+	// PodNamePlaceholder is replaced by the name of the pod of the member
+	PodNamePlaceholder = "${POD_NAME}"
+)
+
+// ServerIDBase returns the server_id of the first member of the group, derived from the group name so that
+// groups replicating from each other do not share server ids. The member of ordinal n has the server_id base + n
+func ServerIDBase(groupName string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(groupName))
+	return h.Sum32()%42949*100000 + 1
+}
+
+// RenderMemberConfig returns the config of the member from the config template shared by the group,
+// the ordinal of the member is the suffix of the name of its pod
+func RenderMemberConfig(template, podName string, serverIDBase uint32) (string, error) {
+	i := strings.LastIndex(podName, "-")
+	if i < 0 {
+		return "", fmt.Errorf("pod name %s has no ordinal", podName)
+	}
+	ordinal, err := strconv.ParseUint(podName[i+1:], 10, 32)
+	if err != nil || ordinal >= 100000 {
+		return "", fmt.Errorf("pod name %s has no valid ordinal", podName)
+	}
+
+	return strings.NewReplacer(
+		ServerIDPlaceholder, strconv.FormatUint(uint64(serverIDBase)+ordinal, 10),
+		PodNamePlaceholder, podName,
+	).Replace(template), nil
 }
 
 // configTemplate is a template for the MySQL configuration file.
@@ -39,6 +87,8 @@ func (c *MySQLConfig) String(cnf MySQLConfig) (string, error) {
 	c.ReportHost = cnf.ReportHost
 	c.ReportPort = cnf.ReportPort
 	c.InnodbBufferPoolSize = cnf.InnodbBufferPoolSize
+	c.EnableSemiSync = cnf.EnableSemiSync
+	c.StorageLayout = cnf.StorageLayout
 
 	// 输出执行路径
 	// fmt.Println(os.Getwd())
@@ -2,6 +2,8 @@ package mysql
 
 import (
 	"log"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -35,3 +37,53 @@ func TestConfig(t *testing.T) {
 		t.Fatalf("File() error: %v", err)
 	}
 }
+
+func TestConfigStorageLayout(t *testing.T) {
+	cnf := new(MySQLConfig)
+	data, err := cnf.String(MySQLConfig{InnodbBufferPoolSize: "1G"})
+	if err != nil {
+		t.Fatalf("String() error: %v", err)
+	}
+	if !strings.Contains(data, "log_bin = /data/GreatSQL/binlog\n") || strings.Contains(data, "innodb_undo_directory") {
+		t.Errorf("String() without a storage layout keeps the files in the data directory")
+	}
+
+	data, err = cnf.String(MySQLConfig{
+		InnodbBufferPoolSize: "1G",
+		StorageLayout:        StorageLayout{BinlogDir: "/binlog", RedoDir: "/redo", UndoDir: "/undo", TmpDir: "/tmpdir"},
+	})
+	if err != nil {
+		t.Fatalf("String() error: %v", err)
+	}
+	for _, line := range []string{
+		"log_bin = /binlog/binlog\n",
+		"innodb_log_group_home_dir = /redo\n",
+		"innodb_undo_directory = /undo\n",
+		"tmpdir = /tmpdir\n",
+	} {
+		if !strings.Contains(data, line) {
+			t.Errorf("String() is missing %q", line)
+		}
+	}
+}
+
+func TestRenderMemberConfig(t *testing.T) {
+	template := "server_id = " + ServerIDPlaceholder + "\nreport_host = " + PodNamePlaceholder + ".mgr-headless.default.svc.cluster.local\n"
+	base := ServerIDBase("aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee")
+	if base == 0 || base%100000 != 1 {
+		t.Fatalf("ServerIDBase() = %d, want the first id of a block", base)
+	}
+
+	cnf, err := RenderMemberConfig(template, "mgr-2", base)
+	if err != nil {
+		t.Fatalf("RenderMemberConfig() error: %v", err)
+	}
+	want := "server_id = " + strconv.FormatUint(uint64(base)+2, 10) + "\nreport_host = mgr-2.mgr-headless.default.svc.cluster.local\n"
+	if cnf != want {
+		t.Errorf("RenderMemberConfig() = %q, want %q", cnf, want)
+	}
+
+	if _, err := RenderMemberConfig(template, "mgr", base); err == nil {
+		t.Error("RenderMemberConfig() without ordinal should fail")
+	}
+}
@@ -0,0 +1,94 @@
+package mysql
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-20 00:31:17
+ * @file: datadir.go
+ * @description: data dir inspection and bootstrap statements of a new instance
+ */
+
+// states of a data dir
+const (
+	DataDirEmpty       = "Empty"
+	DataDirIncomplete  = "Incomplete"
+	DataDirInitialized = "Initialized"
+	DataDirCorrupted   = "Corrupted"
+)
+
+// InitializingMarkerSuffix is the suffix of the marker created next to the data dir while it is initialized,
+// mysqld only initializes an empty data dir. A data dir with the marker was left by an interrupted
+// initialization and can be initialized again
+const InitializingMarkerSuffix = ".initializing"
+
+// InitializingMarker returns the path of the marker of the data dir
+func InitializingMarker(dir string) string {
+	return filepath.Clean(dir) + InitializingMarkerSuffix
+}
+
+// files of every initialized data dir: the tablespace of the data dictionary and the system schema
+const (
+	dataDictionaryFile = "mysql.ibd"
+	systemSchemaDir    = "mysql"
+)
+
+// ignoredDataDirEntries are left in the data dir by the volume or by a failed start, they do not make it initialized
+var ignoredDataDirEntries = map[string]bool{
+	"lost+found": true,
+	"error.log":  true,
+}
+
+// InspectDataDir returns the state of the data dir and the reason of a corrupted one, a missing data dir is empty
+func InspectDataDir(dir string) (string, string, error) {
+	if _, err := os.Stat(InitializingMarker(dir)); err == nil {
+		return DataDirIncomplete, "", nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return DataDirEmpty, "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !ignoredDataDirEntries[entry.Name()] {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) == 0 {
+		return DataDirEmpty, "", nil
+	}
+
+	info, err := os.Stat(filepath.Join(dir, dataDictionaryFile))
+	switch {
+	case os.IsNotExist(err):
+		return DataDirCorrupted, fmt.Sprintf("%s is missing, the data dir holds %s", dataDictionaryFile, strings.Join(names, ", ")), nil
+	case err != nil:
+		return "", "", err
+	case info.Size() == 0:
+		return DataDirCorrupted, fmt.Sprintf("%s is empty", dataDictionaryFile), nil
+	}
+	if info, err := os.Stat(filepath.Join(dir, systemSchemaDir)); err != nil || !info.IsDir() {
+		return DataDirCorrupted, fmt.Sprintf("the %s schema is missing", systemSchemaDir), nil
+	}
+	return DataDirInitialized, "", nil
+}
+
+// BootstrapStatements returns the statements run by mysqld --initialize, they set the root password
+// of the local and of the remote root user
+func BootstrapStatements(rootPassword string) []string {
+	return []string{
+		fmt.Sprintf("ALTER USER 'root'@'localhost' IDENTIFIED BY %s;", quote(rootPassword)),
+		fmt.Sprintf("CREATE USER IF NOT EXISTS 'root'@'%%' IDENTIFIED BY %s;", quote(rootPassword)),
+		"GRANT ALL PRIVILEGES ON *.* TO 'root'@'%' WITH GRANT OPTION;",
+	}
+}
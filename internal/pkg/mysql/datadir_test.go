@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-20 00:46:52
+ * @file: datadir_test.go
+ * @description: data dir inspection test
+ */
+
+func TestInspectDataDir(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		dirs  []string
+		want  string
+	}{
+		{"missing", nil, nil, DataDirEmpty},
+		{"lost+found of the volume", nil, []string{"lost+found"}, DataDirEmpty},
+		{"failed start", map[string]string{"error.log": "[ERROR]"}, nil, DataDirEmpty},
+		{"interrupted initialization", map[string]string{"ibdata1": "x"}, nil, DataDirIncomplete},
+		{"initialized", map[string]string{"mysql.ibd": "x", "ibdata1": "x"}, []string{"mysql", "lost+found"}, DataDirInitialized},
+		{"missing data dictionary", map[string]string{"ibdata1": "x"}, []string{"mysql"}, DataDirCorrupted},
+		{"empty data dictionary", map[string]string{"mysql.ibd": ""}, []string{"mysql"}, DataDirCorrupted},
+		{"missing system schema", map[string]string{"mysql.ibd": "x"}, nil, DataDirCorrupted},
+	}
+	for _, tt := range tests {
+		dir := filepath.Join(t.TempDir(), "GreatSQL")
+		if tt.files != nil || tt.dirs != nil {
+			if err := os.Mkdir(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for name, content := range tt.files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, name := range tt.dirs {
+			if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if tt.want == DataDirIncomplete {
+			if err := os.WriteFile(InitializingMarker(dir), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, reason, err := InspectDataDir(dir)
+		if err != nil {
+			t.Fatalf("%s: InspectDataDir() error = %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: InspectDataDir() = %q, want %q", tt.name, got, tt.want)
+		}
+		if (got == DataDirCorrupted) != (reason != "") {
+			t.Errorf("%s: InspectDataDir() reason = %q", tt.name, reason)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 10:12:40
+ * @file: group.go
+ * @description: group replication membership
+ */
+
+// group replication member state and role, as reported by performance_schema.replication_group_members
+const (
+	MemberStateOnline      string = "ONLINE"
+	MemberStateRecovering  string = "RECOVERING"
+	MemberStateOffline     string = "OFFLINE"
+	MemberStateError       string = "ERROR"
+	MemberStateUnreachable string = "UNREACHABLE"
+
+	MemberRolePrimary    string = "PRIMARY"
+	MemberRoleSecondary  string = "SECONDARY"
+	MemberRoleArbitrator string = "ARBITRATOR"
+)
+
+// GroupMember is a member of the group replication
+type GroupMember struct {
+	ID    string `json:"id"`
+	Host  string `json:"host"`
+	Port  int    `json:"port"`
+	State string `json:"state"`
+	Role  string `json:"role"`
+	// Weight is the group_replication_member_weight of the member, it is only set from GetMemberWeights
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// IsOnline returns true if the member is ONLINE in the group
+func (g *GroupMember) IsOnline() bool {
+	return g.State == MemberStateOnline
+}
+
+// IsPrimary returns true if the member is the ONLINE primary of the group
+func (g *GroupMember) IsPrimary() bool {
+	return g.IsOnline() && g.Role == MemberRolePrimary
+}
+
+// GetGroupMembers returns the members of the group as seen by the connected member
+func (m *MySQL) GetGroupMembers() ([]GroupMember, error) {
+	query := "SELECT MEMBER_ID, MEMBER_HOST, MEMBER_PORT, MEMBER_STATE, MEMBER_ROLE FROM performance_schema.replication_group_members;"
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []GroupMember
+	for rows.Next() {
+		var member GroupMember
+		var port sql.NullInt64
+		if err := rows.Scan(&member.ID, &member.Host, &port, &member.State, &member.Role); err != nil {
+			return nil, err
+		}
+		member.Port = int(port.Int64)
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// SwitchoverTarget returns the ONLINE secondary which takes over the primary role, the one with the highest weight
+// as the election of the group does. Members of the same weight are ordered by host so that every caller picks the same target
+func SwitchoverTarget(members []GroupMember, self string) (GroupMember, bool) {
+	var target GroupMember
+	found := false
+	for _, member := range members {
+		if member.ID == self || !member.IsOnline() || member.Role != MemberRoleSecondary {
+			continue
+		}
+		if !found || member.Weight > target.Weight || member.Weight == target.Weight && member.Host < target.Host {
+			target = member
+			found = true
+		}
+	}
+	return target, found
+}
+
+// GetMemberWeights returns the weight of the members of the group keyed by MEMBER_ID, as exposed by the MEMBER_WEIGHT
+// column of performance_schema.replication_group_members of GreatSQL on the connected member
+func (m *MySQL) GetMemberWeights() (map[string]int32, error) {
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	rows, err := db.Query("SELECT MEMBER_ID, MEMBER_WEIGHT FROM performance_schema.replication_group_members;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	weights := make(map[string]int32)
+	for rows.Next() {
+		var id string
+		var weight sql.NullInt32
+		if err := rows.Scan(&id, &weight); err != nil {
+			return nil, err
+		}
+		weights[id] = weight.Int32
+	}
+	return weights, rows.Err()
+}
+
+// GetServerUUID returns the server_uuid of the connected member, it is the MEMBER_ID in the group
+func (m *MySQL) GetServerUUID() (string, error) {
+	sql := "SELECT @@server_uuid;"
+	var uuid string
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	if err := db.QueryRow(sql).Scan(&uuid); err != nil {
+		return "", err
+	}
+	return uuid, nil
+}
+
+// SetAsPrimary elects the member as the new primary of the group,
+// it returns once the current transactions of the old primary are applied
+func (m *MySQL) SetAsPrimary(memberID string) error {
+	sql := "SELECT group_replication_set_as_primary(?);"
+	return m.executeQuery(sql, memberID)
+}
+
+// MemberWeight is the weight of the member in the election of a new primary
+type MemberWeight struct {
+	Weight int32 `json:"weight"`
+	// Arbitrator is true if the member is an arbitrator, which holds no data and is never elected
+	Arbitrator bool `json:"arbitrator,omitempty"`
+}
+
+// GetMemberWeight returns the group_replication_member_weight of the connected member
+func (m *MySQL) GetMemberWeight() (MemberWeight, error) {
+	var weight MemberWeight
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return weight, err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	if err := db.QueryRow("SELECT @@global.group_replication_member_weight;").Scan(&weight.Weight); err != nil {
+		return weight, err
+	}
+	// group_replication_arbitrator only exists in GreatSQL
+	var arbitrator sql.NullInt64
+	if err := db.QueryRow("SELECT @@global.group_replication_arbitrator;").Scan(&arbitrator); err == nil {
+		weight.Arbitrator = arbitrator.Int64 == 1
+	}
+	return weight, nil
+}
+
+// SetMemberWeight sets and persists the group_replication_member_weight of the connected member,
+// it is used by the next election and kept across restarts
+func (m *MySQL) SetMemberWeight(weight int32) error {
+	return m.executeStatements(fmt.Sprintf("SET PERSIST group_replication_member_weight = %d;", ClampWeight(weight)))
+}
+
+// ClampWeight returns the weight within the range of group_replication_member_weight
+func ClampWeight(weight int32) int32 {
+	switch {
+	case weight < 0:
+		return 0
+	case weight > 100:
+		return 100
+	}
+	return weight
+}
+
+// StopGroupReplication makes the connected member leave the group
+func (m *MySQL) StopGroupReplication() error {
+	sql := "STOP GROUP_REPLICATION;"
+	return m.executeQuery(sql)
+}
+
+// RecoveryChannel is the channel the joining member copies the missing transactions of the group through
+const RecoveryChannel = "group_replication_recovery"
+
+// StartGroupReplication makes the connected member join the group through its seeds with the user of the distributed
+// recovery, the user is kept for the start on boot. A member in ERROR state has to be stopped first
+func (m *MySQL) StartGroupReplication(user, password string) error {
+	return m.executeStatements(
+		"STOP GROUP_REPLICATION;",
+		recoveryCredentials(user, password),
+		"START GROUP_REPLICATION;",
+	)
+}
+
+// BootstrapGroup starts the group on the connected member alone, the other members join it afterwards.
+// group_replication_bootstrap_group is always switched off again so that a restart does not create a second group,
+// the user of the distributed recovery is created on the primary once the group is started so that it replicates
+func (m *MySQL) BootstrapGroup(user, password string) error {
+	err := m.executeStatements(
+		"STOP GROUP_REPLICATION;",
+		recoveryCredentials(user, password),
+		"SET GLOBAL group_replication_bootstrap_group = ON;",
+		"START GROUP_REPLICATION;",
+	)
+	if offErr := m.executeQuery("SET GLOBAL group_replication_bootstrap_group = OFF;"); err == nil {
+		err = offErr
+	}
+	if err != nil {
+		return err
+	}
+	return m.ensureRecoveryUser(user, password)
+}
+
+// recoveryCredentials returns the statement setting the user of the distributed recovery, it is not binlogged
+func recoveryCredentials(user, password string) string {
+	return fmt.Sprintf("CHANGE REPLICATION SOURCE TO SOURCE_USER = %s, SOURCE_PASSWORD = %s FOR CHANNEL %s;",
+		quote(user), quote(password), quote(RecoveryChannel))
+}
+
+// ensureRecoveryUser creates the user of the distributed recovery unless it exists, the data of a cloned or a woken up
+// member already has it and is not written again. Clone based recovery needs BACKUP_ADMIN on the donor
+func (m *MySQL) ensureRecoveryUser(user, password string) error {
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM mysql.user WHERE user = ? AND host = '%';", user).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return m.executeStatements(
+		fmt.Sprintf("CREATE USER %s@'%%' IDENTIFIED BY %s;", quote(user), quote(password)),
+		fmt.Sprintf("GRANT REPLICATION SLAVE, BACKUP_ADMIN ON *.* TO %s@'%%';", quote(user)),
+	)
+}
+
+// GroupStartPlan is how the members of a group are started
+type GroupStartPlan struct {
+	// Online is true if every member is ONLINE in the group
+	Online bool
+	// Join are the members started to join the group running on another member
+	Join []string
+	// Waiting are the members which are not known yet, the group is only bootstrapped once every member is known
+	Waiting []string
+	// Bootstrap is the member the group is bootstrapped from
+	Bootstrap string
+	// Diverged is true if the members have diverged transactions, the group can not be bootstrapped from any of them
+	Diverged bool
+}
+
+// PlanGroupStart returns how the members of the names are started. The states are the states of the members which
+// answered in their own view of the group, the gtids are the gtid_executed of the members which are not part of a group.
+// The members join the group if one of them is part of it, else it is bootstrapped from the member which has executed
+// the most transactions once every member is known. A new group is bootstrapped from the first member
+func PlanGroupStart(names []string, states, gtids map[string]string) GroupStartPlan {
+	var plan GroupStartPlan
+	online, grouped := 0, false
+	for _, name := range names {
+		state, ok := states[name]
+		switch {
+		case !ok:
+		case state == MemberStateOnline:
+			online++
+			grouped = true
+		case state == MemberStateRecovering:
+			grouped = true
+		default:
+			plan.Join = append(plan.Join, name)
+		}
+	}
+	if grouped {
+		plan.Online = online == len(names)
+		return plan
+	}
+	plan.Join = nil
+
+	for _, name := range names {
+		if _, ok := gtids[name]; !ok {
+			plan.Waiting = append(plan.Waiting, name)
+		}
+	}
+	if len(plan.Waiting) > 0 {
+		return plan
+	}
+	candidate, ok := BootstrapCandidate(gtids)
+	if !ok {
+		plan.Diverged = true
+		return plan
+	}
+	plan.Bootstrap = candidate
+	return plan
+}
+
+// BootstrapCandidate returns the member which has executed the most transactions, keyed by member name with its
+// gtid_executed, the lowest name wins a tie. It returns false if another member has executed transactions the
+// candidate does not have, the group can not be bootstrapped from any member without losing them
+func BootstrapCandidate(members map[string]string) (string, bool) {
+	var names []string
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	candidate, most := "", int64(-1)
+	for _, name := range names {
+		count, err := GTIDSetCount(members[name])
+		if err != nil {
+			return "", false
+		}
+		if count > most {
+			candidate, most = name, count
+		}
+	}
+	if candidate == "" {
+		return "", false
+	}
+
+	for _, name := range names {
+		missing, err := GTIDSubtract(members[name], members[candidate])
+		if err != nil || missing != "" {
+			return candidate, false
+		}
+	}
+	return candidate, true
+}
@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 17:04:12
+ * @file: group_test.go
+ * @description: group replication membership test
+ */
+
+func TestSwitchoverTarget(t *testing.T) {
+	members := []GroupMember{
+		{ID: "a", Host: "mgr-0", State: MemberStateOnline, Role: MemberRolePrimary},
+		{ID: "b", Host: "mgr-2", State: MemberStateOnline, Role: MemberRoleSecondary},
+		{ID: "c", Host: "mgr-1", State: MemberStateRecovering, Role: MemberRoleSecondary},
+		{ID: "d", Host: "mgr-3", State: MemberStateOnline, Role: MemberRoleSecondary},
+	}
+
+	target, ok := SwitchoverTarget(members, "a")
+	if !ok || target.ID != "b" {
+		t.Errorf("SwitchoverTarget() = %v, %v, want b", target, ok)
+	}
+
+	members[3].Weight = 80
+	target, ok = SwitchoverTarget(members, "a")
+	if !ok || target.ID != "d" {
+		t.Errorf("SwitchoverTarget() = %v, %v, want d with the highest weight", target, ok)
+	}
+
+	members[1].Weight = 80
+	target, ok = SwitchoverTarget(members, "a")
+	if !ok || target.ID != "b" {
+		t.Errorf("SwitchoverTarget() = %v, %v, want b with the lowest host of the highest weight", target, ok)
+	}
+
+	if _, ok := SwitchoverTarget(members[:1], "a"); ok {
+		t.Error("SwitchoverTarget() without secondary should not find a target")
+	}
+}
+
+func TestPlanGroupStart(t *testing.T) {
+	uuid := "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+	names := []string{"mgr-0", "mgr-1", "mgr-2"}
+	offline := map[string]string{"mgr-0": MemberStateOffline, "mgr-1": MemberStateOffline, "mgr-2": MemberStateOffline}
+
+	tests := []struct {
+		name   string
+		states map[string]string
+		gtids  map[string]string
+		want   GroupStartPlan
+	}{
+		{
+			name:   "new cluster is bootstrapped from the first member",
+			states: offline,
+			gtids:  map[string]string{"mgr-0": "", "mgr-1": "", "mgr-2": ""},
+			want:   GroupStartPlan{Bootstrap: "mgr-0"},
+		},
+		{
+			name:   "new cluster waits for every member",
+			states: map[string]string{"mgr-0": MemberStateOffline, "mgr-2": MemberStateOffline},
+			gtids:  map[string]string{"mgr-0": "", "mgr-2": ""},
+			want:   GroupStartPlan{Waiting: []string{"mgr-1"}},
+		},
+		{
+			name:   "woken up cluster is bootstrapped from the most transactions",
+			states: offline,
+			gtids:  map[string]string{"mgr-0": uuid + ":1-10", "mgr-1": uuid + ":1-12", "mgr-2": uuid + ":1-11"},
+			want:   GroupStartPlan{Bootstrap: "mgr-1"},
+		},
+		{
+			name:   "diverged members block the bootstrap",
+			states: offline,
+			gtids:  map[string]string{"mgr-0": uuid + ":1-10:12", "mgr-1": uuid + ":1-11", "mgr-2": uuid + ":1-11"},
+			want:   GroupStartPlan{Diverged: true},
+		},
+		{
+			name:   "members join the bootstrapped group",
+			states: map[string]string{"mgr-0": MemberStateOffline, "mgr-1": MemberStateOnline, "mgr-2": MemberStateError},
+			gtids:  map[string]string{"mgr-0": "", "mgr-2": ""},
+			want:   GroupStartPlan{Join: []string{"mgr-0", "mgr-2"}},
+		},
+		{
+			name:   "recovering member waits for the group",
+			states: map[string]string{"mgr-0": MemberStateRecovering},
+			want:   GroupStartPlan{},
+		},
+		{
+			name:   "online group",
+			states: map[string]string{"mgr-0": MemberStateOnline, "mgr-1": MemberStateOnline, "mgr-2": MemberStateOnline},
+			want:   GroupStartPlan{Online: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlanGroupStart(names, tt.states, tt.gtids); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanGroupStart() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBootstrapCandidate(t *testing.T) {
+	uuid := "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+	members := map[string]string{
+		"mgr-2": uuid + ":1-10",
+		"mgr-1": uuid + ":1-12",
+		"mgr-3": uuid + ":1-12",
+	}
+	candidate, ok := BootstrapCandidate(members)
+	if !ok || candidate != "mgr-1" {
+		t.Errorf("BootstrapCandidate() = %s, %v, want mgr-1", candidate, ok)
+	}
+
+	members["mgr-3"] = uuid + ":1-11:13"
+	if _, ok := BootstrapCandidate(members); ok {
+		t.Error("BootstrapCandidate() with diverged members should not find a candidate")
+	}
+}
+
+func TestClampWeight(t *testing.T) {
+	for weight, want := range map[int32]int32{-1: 0, 0: 0, 50: 50, 100: 100, 150: 100} {
+		if got := ClampWeight(weight); got != want {
+			t.Errorf("ClampWeight(%d) = %d, want %d", weight, got, want)
+		}
+	}
+}
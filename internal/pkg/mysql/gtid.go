@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 /**
@@ -87,3 +89,107 @@ func GetMaxGTIDMember(gtidArryList []string) (GTID, error) {
 
 	return compareGTIDs(gtids), nil
 }
+
+// gtidInterval is a closed interval of transaction numbers
+type gtidInterval struct {
+	start, end int64
+}
+
+// parseGTIDSet parses a gtid set into the intervals of each uuid, the uuids are returned in order of appearance
+func parseGTIDSet(set string) ([]string, map[string][]gtidInterval, error) {
+	var uuids []string
+	intervals := make(map[string][]gtidInterval)
+	for _, member := range strings.Split(set, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		parts := strings.Split(member, ":")
+		if len(parts) < 2 {
+			return nil, nil, fmt.Errorf("invalid GTID set: %s", member)
+		}
+		uuid := strings.ToLower(parts[0])
+		if _, ok := intervals[uuid]; !ok {
+			uuids = append(uuids, uuid)
+		}
+		for _, interval := range parts[1:] {
+			bounds := strings.SplitN(interval, "-", 2)
+			start, err := strconv.ParseInt(bounds[0], 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid GTID interval: %s", interval)
+			}
+			end := start
+			if len(bounds) == 2 {
+				if end, err = strconv.ParseInt(bounds[1], 10, 64); err != nil {
+					return nil, nil, fmt.Errorf("invalid GTID interval: %s", interval)
+				}
+			}
+			intervals[uuid] = append(intervals[uuid], gtidInterval{start: start, end: end})
+		}
+	}
+	return uuids, intervals, nil
+}
+
+// GTIDSetCount returns the number of transactions of a gtid set, e.g. "uuid1:1-5:7,\nuuid2:1-3" holds 9 transactions
+func GTIDSetCount(set string) (int64, error) {
+	_, intervals, err := parseGTIDSet(set)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, uuidIntervals := range intervals {
+		for _, interval := range uuidIntervals {
+			count += interval.end - interval.start + 1
+		}
+	}
+	return count, nil
+}
+
+// GTIDSubtract returns the transactions of the gtid set a which are not in the gtid set b,
+// like GTID_SUBTRACT of the server, e.g. "uuid1:1-10" minus "uuid1:3-5" is "uuid1:1-2:6-10"
+func GTIDSubtract(a, b string) (string, error) {
+	uuids, intervals, err := parseGTIDSet(a)
+	if err != nil {
+		return "", err
+	}
+	_, subtrahend, err := parseGTIDSet(b)
+	if err != nil {
+		return "", err
+	}
+
+	var members []string
+	for _, uuid := range uuids {
+		remaining := intervals[uuid]
+		for _, cut := range subtrahend[uuid] {
+			var next []gtidInterval
+			for _, interval := range remaining {
+				if cut.end < interval.start || cut.start > interval.end {
+					next = append(next, interval)
+					continue
+				}
+				if interval.start < cut.start {
+					next = append(next, gtidInterval{start: interval.start, end: cut.start - 1})
+				}
+				if interval.end > cut.end {
+					next = append(next, gtidInterval{start: cut.end + 1, end: interval.end})
+				}
+			}
+			remaining = next
+		}
+		if len(remaining) == 0 {
+			continue
+		}
+
+		sort.Slice(remaining, func(i, j int) bool { return remaining[i].start < remaining[j].start })
+		member := uuid
+		for _, interval := range remaining {
+			if interval.start == interval.end {
+				member += fmt.Sprintf(":%d", interval.start)
+			} else {
+				member += fmt.Sprintf(":%d-%d", interval.start, interval.end)
+			}
+		}
+		members = append(members, member)
+	}
+	return strings.Join(members, ","), nil
+}
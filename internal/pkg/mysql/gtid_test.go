@@ -26,3 +26,53 @@ func TestGetMaxGTIDMember(t *testing.T) {
 
 	fmt.Printf("Node with max GTID: %s:%d-%d\n", maxGTIDMember.UUID, maxGTIDMember.Start, maxGTIDMember.End)
 }
+
+func TestGTIDSetCount(t *testing.T) {
+	tests := []struct {
+		set  string
+		want int64
+	}{
+		{"", 0},
+		{"3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-5", 5},
+		{"3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-5:7:9-10,\n46dda72d-ceec-11ee-be3f-d08e7908bcb1:1-3", 11},
+	}
+	for _, tt := range tests {
+		got, err := GTIDSetCount(tt.set)
+		if err != nil {
+			t.Fatalf("GTIDSetCount(%q) error: %v", tt.set, err)
+		}
+		if got != tt.want {
+			t.Errorf("GTIDSetCount(%q) = %d, want %d", tt.set, got, tt.want)
+		}
+	}
+
+	if _, err := GTIDSetCount("3f65a290-a2f8-11ee-acdd-d08e7908bcb1"); err == nil {
+		t.Error("GTIDSetCount() expected an error for a set without interval")
+	}
+}
+
+func TestGTIDSubtract(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-5", ""},
+		{"3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-5", "", "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-5"},
+		{"3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-10", "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:3-5", "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-2:6-10"},
+		{"3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-10", "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-9", "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:10"},
+		{
+			"3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-10,\n46dda72d-ceec-11ee-be3f-d08e7908bcb1:1-3",
+			"3F65A290-A2F8-11EE-ACDD-D08E7908BCB1:1-20,46dda72d-ceec-11ee-be3f-d08e7908bcb1:2",
+			"46dda72d-ceec-11ee-be3f-d08e7908bcb1:1:3",
+		},
+	}
+	for _, tt := range tests {
+		got, err := GTIDSubtract(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("GTIDSubtract(%q, %q) error: %v", tt.a, tt.b, err)
+		}
+		if got != tt.want {
+			t.Errorf("GTIDSubtract(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"fmt"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 11:03:27
+ * @file: lag.go
+ * @description: group member applier lag
+ */
+
+// MemberLag is the applier lag of a group member
+type MemberLag struct {
+	// TransactionsInQueue is the number of remote transactions waiting in the applier queue
+	TransactionsInQueue int64 `json:"transactionsInQueue"`
+	// SecondsBehind is the age of the oldest transaction being applied
+	SecondsBehind int64 `json:"secondsBehind"`
+}
+
+// LagThreshold decides whether a secondary serves reads according to its lag
+type LagThreshold struct {
+	// MaxTransactionsInQueue 0 means no limit
+	MaxTransactionsInQueue int64
+	// MaxSecondsBehind 0 means no limit
+	MaxSecondsBehind int64
+	// RecoveryPercent is the percentage of the thresholds the lag has to fall below
+	// before an excluded secondary serves reads again
+	RecoveryPercent int64
+}
+
+// ServeReads returns whether the secondary should serve reads,
+// serving is the current decision so that a member close to the threshold does not flap
+func (t LagThreshold) ServeReads(serving bool, lag MemberLag) bool {
+	if serving {
+		return !exceeds(lag.TransactionsInQueue, t.MaxTransactionsInQueue) &&
+			!exceeds(lag.SecondsBehind, t.MaxSecondsBehind)
+	}
+
+	recovery := t.RecoveryPercent
+	if recovery <= 0 || recovery > 100 {
+		recovery = 100
+	}
+	return !exceeds(lag.TransactionsInQueue, t.MaxTransactionsInQueue*recovery/100) &&
+		!exceeds(lag.SecondsBehind, t.MaxSecondsBehind*recovery/100)
+}
+
+// exceeds returns true if the value passes a positive limit
+func exceeds(value, limit int64) bool {
+	return limit > 0 && value > limit
+}
+
+// GetMemberLag returns the applier lag of the connected member
+func (m *MySQL) GetMemberLag() (MemberLag, error) {
+	queueSQL := "SELECT COUNT_TRANSACTIONS_REMOTE_IN_APPLIER_QUEUE FROM performance_schema.replication_group_member_stats WHERE MEMBER_ID = @@server_uuid;"
+	secondsSQL := "SELECT IFNULL(MAX(TIMESTAMPDIFF(SECOND, APPLYING_TRANSACTION_ORIGINAL_COMMIT_TIMESTAMP, NOW(6))), 0) " +
+		"FROM performance_schema.replication_applier_status_by_worker " +
+		"WHERE CHANNEL_NAME = 'group_replication_applier' AND APPLYING_TRANSACTION <> '';"
+
+	var lag MemberLag
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return lag, err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	if err := db.QueryRow(queueSQL).Scan(&lag.TransactionsInQueue); err != nil {
+		return lag, err
+	}
+	if err := db.QueryRow(secondsSQL).Scan(&lag.SecondsBehind); err != nil {
+		return lag, err
+	}
+
+	return lag, nil
+}
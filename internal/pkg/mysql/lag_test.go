@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"testing"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 11:20:05
+ * @file: lag_test.go
+ * @description: group member applier lag test
+ */
+
+func TestServeReads(t *testing.T) {
+	threshold := LagThreshold{
+		MaxTransactionsInQueue: 1000,
+		MaxSecondsBehind:       10,
+		RecoveryPercent:        50,
+	}
+
+	cases := []struct {
+		name    string
+		serving bool
+		lag     MemberLag
+		want    bool
+	}{
+		{"serving without lag", true, MemberLag{}, true},
+		{"serving at the threshold", true, MemberLag{TransactionsInQueue: 1000, SecondsBehind: 10}, true},
+		{"serving over the queue threshold", true, MemberLag{TransactionsInQueue: 1001}, false},
+		{"serving over the seconds threshold", true, MemberLag{SecondsBehind: 11}, false},
+		{"excluded under the threshold but over recovery", false, MemberLag{TransactionsInQueue: 800}, false},
+		{"excluded under recovery", false, MemberLag{TransactionsInQueue: 400, SecondsBehind: 5}, true},
+	}
+
+	for _, c := range cases {
+		if got := threshold.ServeReads(c.serving, c.lag); got != c.want {
+			t.Errorf("%s: ServeReads() = %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	// no threshold configured, every secondary serves reads
+	if !(LagThreshold{}).ServeReads(false, MemberLag{TransactionsInQueue: 1 << 20, SecondsBehind: 3600}) {
+		t.Error("ServeReads() without threshold should be true")
+	}
+}
@@ -3,6 +3,7 @@ package mysql
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -21,11 +22,21 @@ type MySQL struct {
 	Host     string
 	Port     int32
 	DB       string
+	// Timeout is the dial, read and write timeout, 0 means the driver default
+	Timeout time.Duration
+	// MultiStatements allows several statements separated by semicolons in a query
+	MultiStatements bool
 }
 
 // NewClient create a new mysql client
 func (m *MySQL) NewClient(username, password, host, db string, port int32) (*sql.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", username, password, host, port, db)
+	if m.Timeout > 0 {
+		dsn += fmt.Sprintf("&timeout=%s&readTimeout=%s&writeTimeout=%s", m.Timeout, m.Timeout, m.Timeout)
+	}
+	if m.MultiStatements {
+		dsn += "&multiStatements=true"
+	}
 
 	dbConn, err := sql.Open("mysql", dsn)
 	if err != nil {
@@ -63,6 +74,14 @@ func (m *MySQL) executeQuery(query string, args ...interface{}) error {
 	return nil
 }
 
+// ExecScript executes the statements of the sql script separated by semicolons, client side commands
+// such as DELIMITER are not supported
+func (m *MySQL) ExecScript(script string) error {
+	client := *m
+	client.MultiStatements = true
+	return client.executeQuery(script)
+}
+
 // GetGTID get gtid
 func (m *MySQL) GetGTID() (string, error) {
 	sql := "SELECT @@global.gtid_executed;"
@@ -86,6 +105,27 @@ func (m *MySQL) GetGTID() (string, error) {
 	return gtid, nil
 }
 
+// GetVersion returns the server version of the connected member
+func (m *MySQL) GetVersion() (string, error) {
+	sql := "SELECT @@global.version;"
+	var version string
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	if err := db.QueryRow(sql).Scan(&version); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
 // ModifyRootPassword modify root password
 func (m *MySQL) ModifyRootPassword(password string) error {
 	sql := "ALTER USER 'root'@'%' IDENTIFIED BY ?;"
@@ -110,3 +150,37 @@ func (m *MySQL) SetBootstrapMember() error {
 	sql := "SET GLOBAL group_replication_bootstrap_group=ON;"
 	return m.executeQuery(sql)
 }
+
+// Ping checks the connected member accepts queries
+func (m *MySQL) Ping() error {
+	return m.executeQuery("SELECT 1;")
+}
+
+// GetMemberState returns the MEMBER_STATE of the connected member in the group
+func (m *MySQL) GetMemberState() (string, error) {
+	sql := "SELECT MEMBER_STATE FROM performance_schema.replication_group_members WHERE MEMBER_ID = @@server_uuid;"
+	var state string
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	err = db.QueryRow(sql).Scan(&state)
+	if err != nil {
+		return "", err
+	}
+
+	return state, nil
+}
+
+// Shutdown shuts down the connected mysqld cleanly
+func (m *MySQL) Shutdown() error {
+	sql := "SHUTDOWN;"
+	return m.executeQuery(sql)
+}
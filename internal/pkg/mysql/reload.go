@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 15:12:08
+ * @file: reload.go
+ * @description: reload the dynamic variables of my.cnf
+ */
+
+// variableName is a valid system variable name, anything else is not sent to the server
+var variableName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// ParseMysqldSection returns the variables of the [mysqld] section of my.cnf in file order,
+// dashes are normalized to underscores, the loose prefix is dropped and options without value are skipped
+func ParseMysqldSection(r io.Reader) ([][2]string, error) {
+	var variables [][2]string
+	inMysqld := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			inMysqld = strings.TrimSpace(strings.Trim(line, "[]")) == "mysqld"
+			continue
+		}
+		if !inMysqld {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimPrefix(strings.ReplaceAll(strings.TrimSpace(key), "-", "_"), "loose_")
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		variables = append(variables, [2]string{key, value})
+	}
+	return variables, scanner.Err()
+}
+
+// ReloadResult is the result of a config reload
+type ReloadResult struct {
+	// Applied are the variables changed at runtime
+	Applied []string `json:"applied"`
+	// Restart are the variables which only take effect after a restart, with the reason
+	Restart map[string]string `json:"restart,omitempty"`
+}
+
+// ReloadVariables sets the dynamic variables at runtime, the variables already at the desired value are left untouched
+func (m *MySQL) ReloadVariables(variables [][2]string) (ReloadResult, error) {
+	result := ReloadResult{Restart: map[string]string{}}
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return result, err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	for _, variable := range variables {
+		name, value := variable[0], variable[1]
+		if !variableName.MatchString(name) {
+			result.Restart[name] = "invalid variable name"
+			continue
+		}
+
+		var current string
+		if err := db.QueryRow("SELECT @@GLOBAL." + name).Scan(&current); err != nil {
+			// startup options such as datadir or plugin-load have no global variable
+			result.Restart[name] = err.Error()
+			continue
+		}
+		desired := variableValue(value)
+		if strings.EqualFold(current, fmt.Sprint(desired)) {
+			continue
+		}
+
+		if _, err := db.Exec("SET GLOBAL "+name+" = ?", desired); err != nil {
+			var serverErr *driver.MySQLError
+			if !errors.As(err, &serverErr) {
+				return result, err
+			}
+			result.Restart[name] = serverErr.Message
+			continue
+		}
+		result.Applied = append(result.Applied, name)
+	}
+
+	return result, nil
+}
+
+// variableValue returns the value to set, SET GLOBAL rejects numeric variables given as strings
+// and does not accept the K, M and G suffixes of my.cnf
+func variableValue(value string) interface{} {
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n
+	}
+
+	multipliers := map[byte]int64{'K': 1 << 10, 'M': 1 << 20, 'G': 1 << 30}
+	if len(value) > 1 {
+		if multiplier, ok := multipliers[value[len(value)-1]&^0x20]; ok {
+			if n, err := strconv.ParseInt(value[:len(value)-1], 10, 64); err == nil {
+				return n * multiplier
+			}
+		}
+	}
+	return value
+}
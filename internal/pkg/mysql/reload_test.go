@@ -0,0 +1,53 @@
+package mysql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 15:26:51
+ * @file: reload_test.go
+ * @description: reload my.cnf variables test
+ */
+
+func TestParseMysqldSection(t *testing.T) {
+	cnf := `
+[client]
+socket = /data/GreatSQL/mysql.sock
+[mysqld]
+# comment
+max_connections = 1024
+loose-group_replication_consistency = "BEFORE_ON_PRIMARY_FAILOVER"
+skip_name_resolve
+sort_buffer_size = 4M
+[mysqldump]
+quick = 1
+`
+	variables, err := ParseMysqldSection(strings.NewReader(cnf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][2]string{
+		{"max_connections", "1024"},
+		{"group_replication_consistency", "BEFORE_ON_PRIMARY_FAILOVER"},
+		{"sort_buffer_size", "4M"},
+	}
+	if !reflect.DeepEqual(variables, want) {
+		t.Errorf("ParseMysqldSection() = %v, want %v", variables, want)
+	}
+
+	for value, want := range map[string]interface{}{
+		"1024": int64(1024),
+		"4M":   int64(4 << 20),
+		"1g":   int64(1 << 30),
+		"ON":   "ON",
+	} {
+		if got := variableValue(value); got != want {
+			t.Errorf("variableValue(%s) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 19:52:08
+ * @file: replication.go
+ * @description: gtid based async and semi-sync replication
+ */
+
+// ReplicationSource is the source a replica replicates from
+type ReplicationSource struct {
+	Host string `json:"host"`
+	Port int32  `json:"port"`
+	// ServerID is the server_id of the replica, unique in the cluster
+	ServerID int `json:"serverID"`
+	// SemiSync enables the semi-sync replica plugin
+	SemiSync bool `json:"semiSync"`
+}
+
+// PromoteRequest promotes a member to the writable source
+type PromoteRequest struct {
+	// ServerID is the server_id of the source, unique in the cluster
+	ServerID int `json:"serverID"`
+	// SemiSync enables the semi-sync source plugin
+	SemiSync bool `json:"semiSync"`
+}
+
+// ReplicaStatus is the replication state of a member
+type ReplicaStatus struct {
+	ServerID     int    `json:"serverID"`
+	ReadOnly     bool   `json:"readOnly"`
+	GTIDExecuted string `json:"gtidExecuted"`
+	// RetrievedGTIDSet is Retrieved_Gtid_Set, the transactions received in the relay log which may not be applied yet
+	RetrievedGTIDSet string `json:"retrievedGtidSet,omitempty"`
+	// Replica is false if the member has no replication channel, i.e. it is a source
+	Replica    bool   `json:"replica"`
+	SourceHost string `json:"sourceHost,omitempty"`
+	SourcePort int    `json:"sourcePort,omitempty"`
+	IORunning  string `json:"ioRunning,omitempty"`
+	SQLRunning string `json:"sqlRunning,omitempty"`
+	// SecondsBehind is Seconds_Behind_Source, -1 when it is unknown
+	SecondsBehind int64  `json:"secondsBehind"`
+	LastError     string `json:"lastError,omitempty"`
+}
+
+// IsRunning returns true if both replication threads of the replica are running
+func (r *ReplicaStatus) IsRunning() bool {
+	return r.Replica && r.IORunning == "Yes" && r.SQLRunning == "Yes"
+}
+
+// IsReplicaOf returns true if the member replicates from the given source
+func (r *ReplicaStatus) IsReplicaOf(host string, port int32) bool {
+	return r.Replica && r.SourceHost == host && r.SourcePort == int(port)
+}
+
+// State returns a short summary of the replication threads
+func (r *ReplicaStatus) State() string {
+	switch {
+	case !r.Replica:
+		return "Source"
+	case r.IsRunning():
+		return "Running"
+	case r.LastError != "":
+		return "Error"
+	case r.IORunning == "Connecting":
+		return "Connecting"
+	default:
+		return "Stopped"
+	}
+}
+
+// PromotionCandidate returns the replica of the source which has executed or received the most transactions,
+// Promote applies the relay log of the replica before it takes writes,
+// the lowest name wins a tie, empty if no member replicates from the source
+func PromotionCandidate(members map[string]ReplicaStatus, sourceHost string, sourcePort int32) string {
+	var names []string
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	candidate, most := "", int64(-1)
+	for _, name := range names {
+		member := members[name]
+		if !member.IsReplicaOf(sourceHost, sourcePort) {
+			continue
+		}
+		count, err := member.transactionCount()
+		if err != nil {
+			continue
+		}
+		if count > most {
+			candidate, most = name, count
+		}
+	}
+	return candidate
+}
+
+// transactionCount returns the number of transactions the member has executed or received in its relay log
+func (r *ReplicaStatus) transactionCount() (int64, error) {
+	executed, err := GTIDSetCount(r.GTIDExecuted)
+	if err != nil {
+		return 0, err
+	}
+	pending, err := GTIDSubtract(r.RetrievedGTIDSet, r.GTIDExecuted)
+	if err != nil {
+		return 0, err
+	}
+	received, err := GTIDSetCount(pending)
+	if err != nil {
+		return 0, err
+	}
+	return executed + received, nil
+}
+
+// GetReplicaStatus returns the replication state of the connected member
+func (m *MySQL) GetReplicaStatus() (ReplicaStatus, error) {
+	return m.getReplicaStatus("SHOW REPLICA STATUS;")
+}
+
+// getReplicaStatus returns the replication state of the connected member, the query shows the replica status of the channel
+func (m *MySQL) getReplicaStatus(query string) (ReplicaStatus, error) {
+	status := ReplicaStatus{SecondsBehind: -1}
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return status, err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	err = db.QueryRow("SELECT @@global.server_id, @@global.read_only, @@global.gtid_executed;").
+		Scan(&status.ServerID, &status.ReadOnly, &status.GTIDExecuted)
+	if err != nil {
+		return status, err
+	}
+
+	row, err := queryRowMap(db, query)
+	if err != nil || row == nil {
+		return status, err
+	}
+
+	status.Replica = true
+	status.SourceHost = row["Source_Host"]
+	status.SourcePort, _ = strconv.Atoi(row["Source_Port"])
+	status.RetrievedGTIDSet = row["Retrieved_Gtid_Set"]
+	status.IORunning = row["Replica_IO_Running"]
+	status.SQLRunning = row["Replica_SQL_Running"]
+	if seconds, err := strconv.ParseInt(row["Seconds_Behind_Source"], 10, 64); err == nil {
+		status.SecondsBehind = seconds
+	}
+	status.LastError = row["Last_IO_Error"]
+	if status.LastError == "" {
+		status.LastError = row["Last_SQL_Error"]
+	}
+	return status, nil
+}
+
+// StartReplica points the connected member to the source with GTID auto position
+// and starts replicating, the member is made read-only first
+func (m *MySQL) StartReplica(source ReplicationSource, user, password string) error {
+	statements := []string{
+		"STOP REPLICA;",
+		"SET PERSIST super_read_only = ON;",
+		fmt.Sprintf("SET PERSIST server_id = %d;", source.ServerID),
+		fmt.Sprintf("CHANGE REPLICATION SOURCE TO SOURCE_HOST = %s, SOURCE_PORT = %d, SOURCE_USER = %s, SOURCE_PASSWORD = %s, "+
+			"SOURCE_AUTO_POSITION = 1, GET_SOURCE_PUBLIC_KEY = 1, SOURCE_CONNECT_RETRY = 10;",
+			quote(source.Host), source.Port, quote(user), quote(password)),
+	}
+	if source.SemiSync {
+		statements = append(statements, "SET PERSIST rpl_semi_sync_replica_enabled = ON;")
+	}
+	statements = append(statements, "START REPLICA;")
+	return m.executeStatements(statements...)
+}
+
+// Promote makes the connected member the writable source, the relay log already received
+// is applied before the replication channel is removed
+func (m *MySQL) Promote(req PromoteRequest) error {
+	status, err := m.GetReplicaStatus()
+	if err != nil {
+		return err
+	}
+	if status.Replica {
+		if err := m.executeQuery("STOP REPLICA IO_THREAD;"); err != nil {
+			return err
+		}
+		if err := m.waitForRelayLog("SHOW REPLICA STATUS;"); err != nil {
+			return err
+		}
+	}
+
+	statements := []string{
+		"STOP REPLICA;",
+		"RESET REPLICA ALL;",
+		fmt.Sprintf("SET PERSIST server_id = %d;", req.ServerID),
+		"SET PERSIST super_read_only = OFF;",
+		"SET PERSIST read_only = OFF;",
+	}
+	if req.SemiSync {
+		statements = append(statements, "SET PERSIST rpl_semi_sync_source_enabled = ON;")
+	}
+	return m.executeStatements(statements...)
+}
+
+// EnsureReplicationUser creates the replication user on the source if it does not exist yet
+func (m *MySQL) EnsureReplicationUser(username, password string) error {
+	if err := m.executeQuery("CREATE USER IF NOT EXISTS ?@'%' IDENTIFIED BY ?;", username, password); err != nil {
+		return err
+	}
+	return m.executeQuery("GRANT REPLICATION SLAVE ON *.* TO ?@'%';", username)
+}
+
+// SetReadOnly makes the connected instance read-only, the transactions already committed are kept
+func (m *MySQL) SetReadOnly() error {
+	return m.executeStatements("SET GLOBAL super_read_only = ON;")
+}
+
+// waitForRelayLog waits for the SQL thread to apply the transactions already received,
+// the query shows the replica status of the channel
+func (m *MySQL) waitForRelayLog(query string) error {
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	row, err := queryRowMap(db, query)
+	if err != nil || row == nil || row["Retrieved_Gtid_Set"] == "" {
+		return err
+	}
+	var timedOut int
+	if err := db.QueryRow("SELECT WAIT_FOR_EXECUTED_GTID_SET(?, 60);", row["Retrieved_Gtid_Set"]).Scan(&timedOut); err != nil {
+		return err
+	}
+	if timedOut != 0 {
+		return fmt.Errorf("timed out applying the relay log %s", row["Retrieved_Gtid_Set"])
+	}
+	return nil
+}
+
+// executeStatements executes the statements in order on one connection
+func (m *MySQL) executeStatements(statements ...string) error {
+	db, err := m.NewClient(m.UserName, m.Password, m.Host, m.DB, m.Port)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("could not execute %s: %v", statementName(statement), err)
+		}
+	}
+	return nil
+}
+
+// queryRowMap returns the first row of the query keyed by column name, nil if there is no row
+func queryRowMap(db *sql.DB, query string) (map[string]string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]string, len(columns))
+	for i, column := range columns {
+		row[column] = values[i].String
+	}
+	return row, nil
+}
+
+// statementName returns the first words of the statement, the values are left out of the errors
+func statementName(statement string) string {
+	words := strings.Fields(strings.TrimSuffix(statement, ";"))
+	if len(words) > 3 {
+		words = words[:3]
+	}
+	return strings.Join(words, " ")
+}
+
+// quote returns the string as a quoted sql literal, for the statements which can not be prepared
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
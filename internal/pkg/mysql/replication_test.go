@@ -0,0 +1,66 @@
+package mysql
+
+import "testing"
+
+func TestPromotionCandidate(t *testing.T) {
+	source := "demo-0.demo-headless.default.svc.cluster.local"
+	replica := func(host, gtid string) ReplicaStatus {
+		return ReplicaStatus{Replica: true, SourceHost: host, SourcePort: 3306, GTIDExecuted: gtid}
+	}
+
+	tests := []struct {
+		name    string
+		members map[string]ReplicaStatus
+		want    string
+	}{
+		{
+			name: "most advanced replica",
+			members: map[string]ReplicaStatus{
+				"demo-1": replica(source, "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-10"),
+				"demo-2": replica(source, "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-12"),
+			},
+			want: "demo-2",
+		},
+		{
+			name: "lowest name on tie",
+			members: map[string]ReplicaStatus{
+				"demo-2": replica(source, "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-12"),
+				"demo-1": replica(source, "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-12"),
+			},
+			want: "demo-1",
+		},
+		{
+			name: "received transactions count",
+			members: map[string]ReplicaStatus{
+				"demo-1": replica(source, "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-12"),
+				"demo-2": {
+					Replica: true, SourceHost: source, SourcePort: 3306,
+					GTIDExecuted:     "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-10",
+					RetrievedGTIDSet: "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:5-15",
+				},
+			},
+			want: "demo-2",
+		},
+		{
+			name: "replicas of another source are skipped",
+			members: map[string]ReplicaStatus{
+				"demo-1": replica("other", "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-20"),
+				"demo-2": {GTIDExecuted: "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-30"},
+				"demo-3": replica(source, "3f65a290-a2f8-11ee-acdd-d08e7908bcb1:1-5"),
+			},
+			want: "demo-3",
+		},
+		{
+			name:    "no replica",
+			members: map[string]ReplicaStatus{"demo-1": {}},
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PromotionCandidate(tt.members, source, 3306); got != tt.want {
+				t.Errorf("PromotionCandidate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package toolbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 23:58:04
+ * @file: config.go
+ * @description: render my.cnf of the member from the config template shared by the group
+ */
+
+var (
+	templateFile string
+	outputFile   string
+	serverIDBase uint32
+)
+
+var RenderConfigCmd = &cobra.Command{
+	Use:   "render-config",
+	Short: "Render my.cnf of the member from the config template shared by the group",
+	Run: func(cmd *cobra.Command, args []string) {
+		podName := os.Getenv(consts.PodNameEnv)
+		if podName == "" {
+			podName, _ = os.Hostname()
+		}
+		if err := RenderConfig(templateFile, outputFile, podName, serverIDBase); err != nil {
+			fmt.Fprintf(os.Stderr, "render config error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RenderConfigCmd.Flags().StringVar(&templateFile, "template", consts.ConfigTemplateDir+consts.ConfigFile, "The path of the config template.")
+	RenderConfigCmd.Flags().StringVar(&outputFile, "output", consts.ConfigRenderDir+consts.ConfigFile, "The path of the rendered my.cnf.")
+	RenderConfigCmd.Flags().Uint32Var(&serverIDBase, "server-id-base", 1, "The server_id of the member of ordinal 0.")
+}
+
+// RenderConfig writes my.cnf of the pod rendered from the template
+func RenderConfig(templateFile, outputFile, podName string, serverIDBase uint32) error {
+	template, err := os.ReadFile(templateFile)
+	if err != nil {
+		return err
+	}
+	config, err := mysql.RenderMemberConfig(string(template), podName, serverIDBase)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return err
+	}
+	// write to a temporary file first, a restarted init container must not leave a truncated config
+	tmp := outputFile + ".tmp"
+	if err := os.WriteFile(tmp, []byte(config), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, outputFile)
+}
@@ -0,0 +1,243 @@
+package toolbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	"github.com/gagraler/greatsql-operator/internal/pkg/health"
+	"github.com/gagraler/greatsql-operator/internal/pkg/mysql"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-20 01:05:33
+ * @file: datadir.go
+ * @description: initialize the data dir of the greatsql container
+ */
+
+// ErrDataDirCorrupted is returned when the data dir is neither empty nor initialized
+var ErrDataDirCorrupted = errors.New("data dir is corrupted")
+
+var (
+	dataDir      string
+	defaultsFile string
+	mysqldBinary string
+)
+
+var InitDataDirCmd = &cobra.Command{
+	Use:   "init-datadir",
+	Short: "Initialize the data dir of the GreatSQL pod before mysqld is started",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := InitDataDir(dataDir, defaultsFile, mysqldBinary)
+		if errors.Is(err, ErrDataDirCorrupted) {
+			err = reportCorrupted(err)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "init datadir error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// corruptedPollInterval is the interval the data dir is inspected again at once its corruption is reported
+const corruptedPollInterval = 30 * time.Second
+
+// reportCorrupted exits with the code the controller reports the pods failed with the first time the data dir
+// of the pod is found corrupted, the marker in the tools volume lives as long as the pod. The restarted container
+// blocks instead of crash looping until the data dir is repaired or the pod is deleted
+func reportCorrupted(corrupted error) error {
+	marker := filepath.Join(consts.ToolsDir, "datadir-corrupted")
+	if _, err := os.Stat(marker); os.IsNotExist(err) {
+		if err := os.WriteFile(marker, []byte(corrupted.Error()), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "init datadir error: %v\n", corrupted)
+		os.Exit(int(consts.DataDirCorruptedExitCode))
+	}
+
+	fmt.Fprintf(os.Stderr, "%v, waiting for it to be repaired\n", corrupted)
+	for {
+		time.Sleep(corruptedPollInterval)
+		state, _, err := mysql.InspectDataDir(dataDir)
+		if err != nil {
+			return err
+		}
+		if state != mysql.DataDirCorrupted {
+			if err := os.Remove(marker); err != nil {
+				return err
+			}
+			return InitDataDir(dataDir, defaultsFile, mysqldBinary)
+		}
+	}
+}
+
+func init() {
+	InitDataDirCmd.Flags().StringVar(&dataDir, "datadir", consts.DataSubDir, "The data dir of mysqld.")
+	InitDataDirCmd.Flags().StringVar(&defaultsFile, "defaults-file", consts.ConfigDir+consts.ConfigFile, "The my.cnf of mysqld.")
+	InitDataDirCmd.Flags().StringVar(&mysqldBinary, "mysqld", "mysqld", "The mysqld binary.")
+}
+
+// InitDataDir initializes an empty data dir with the root password of the MYSQL_ROOT_PASSWORD env,
+// an initialized data dir is kept. The data dir and the volumes of the storage layout are owned by the mysql user
+func InitDataDir(dir, defaultsFile, mysqld string) error {
+	state, reason, err := mysql.InspectDataDir(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("data dir %s is %s\n", dir, strings.ToLower(state))
+
+	switch state {
+	case mysql.DataDirCorrupted:
+		return fmt.Errorf("%w: %s: %s", ErrDataDirCorrupted, dir, reason)
+	case mysql.DataDirIncomplete:
+		// the files of the interrupted initialization are removed, the layout volumes may hold some of them as well
+		for _, path := range append([]string{dir}, layoutDirs()...) {
+			if err := removeContents(path); err != nil {
+				return err
+			}
+		}
+	case mysql.DataDirEmpty:
+		// mysqld only initializes an empty data dir, the logs of a failed start are removed
+		if err := removeContents(dir); err != nil {
+			return err
+		}
+	}
+
+	// the data dir is a subdir of the volume, a lost+found dir in the volume root does not break the initialization
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	uid, gid, err := mysqlOwner()
+	if err != nil {
+		return err
+	}
+	for _, path := range append([]string{dir}, layoutDirs()...) {
+		if err := chownTree(path, uid, gid); err != nil {
+			return err
+		}
+	}
+	if state == mysql.DataDirInitialized {
+		return nil
+	}
+
+	marker := mysql.InitializingMarker(dir)
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		return err
+	}
+	if err := initialize(dir, defaultsFile, mysqld, uid, gid); err != nil {
+		return err
+	}
+	fmt.Printf("data dir %s is initialized\n", dir)
+	return os.Remove(marker)
+}
+
+// initialize runs mysqld --initialize-insecure, the init file sets the root password. The init sql scripts
+// of the spec are applied by the controller once the instance is available
+func initialize(dir, defaultsFile, mysqld string, uid, gid int) error {
+	statements := mysql.BootstrapStatements(health.RootPassword())
+
+	// the init file holds the root password, it is only readable by mysqld and removed once the data dir is initialized
+	initFile := filepath.Join(filepath.Dir(filepath.Clean(dir)), ".init.sql")
+	content := strings.Join(statements, "\n") + "\n"
+	if err := os.WriteFile(initFile, []byte(content), 0600); err != nil {
+		return err
+	}
+	defer os.Remove(initFile)
+	if uid >= 0 {
+		if err := os.Chown(initFile, uid, gid); err != nil {
+			return err
+		}
+	}
+
+	cmd := exec.Command(mysqld, "--defaults-file="+defaultsFile, "--datadir="+dir, "--initialize-insecure", "--init-file="+initFile)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("mysqld --initialize: %w", err)
+	}
+	return nil
+}
+
+// layoutDirs returns the mount paths of the volumes of the storage layout mounted in the container
+func layoutDirs() []string {
+	var dirs []string
+	for _, path := range []string{consts.BinlogDir, consts.RedoDir, consts.UndoDir, consts.TmpDir} {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			dirs = append(dirs, path)
+		}
+	}
+	return dirs
+}
+
+// removeContents removes the entries of the dir but the lost+found dir of the volume
+func removeContents(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == "lost+found" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mysqlOwner returns the uid and gid of the mysql user, -1 if the ownership can not be changed
+// because the container does not run as root
+func mysqlOwner() (int, int, error) {
+	if os.Geteuid() != 0 {
+		return -1, -1, nil
+	}
+	u, err := user.Lookup(consts.MySQLOSUser)
+	if err != nil {
+		return -1, -1, err
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return -1, -1, err
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return -1, -1, err
+	}
+	return uid, gid, nil
+}
+
+// chownTree changes the owner of the entries of the dir which are not owned by uid and gid yet
+func chownTree(dir string, uid, gid int) error {
+	if uid < 0 {
+		return nil
+	}
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) == uid && int(stat.Gid) == gid {
+			return nil
+		}
+		return os.Lchown(path, uid, gid)
+	})
+}
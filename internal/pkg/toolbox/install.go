@@ -0,0 +1,69 @@
+package toolbox
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gagraler/greatsql-operator/internal/consts"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 14:20:36
+ * @file: install.go
+ * @description: install the operator binary into the shared tools volume
+ */
+
+var InstallCmd = &cobra.Command{
+	Use:   "install-tools [dir]",
+	Short: "Install the operator tools into the shared tools volume of the GreatSQL pod",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := consts.ToolsDir
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		if err := Install(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "install tools error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// Install copies the running binary into dir, the greatsql container runs it as the exec probe
+func Install(dir string) error {
+	src, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, a restarted init container must not leave a truncated binary
+	dst := filepath.Join(dir, consts.ToolsBinary)
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -2,14 +2,14 @@ package utils
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"sort"
+	"sync"
 
-	singlev1 "github.com/gagraler/greatsql-operator/api/v1"
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
 	"github.com/gagraler/greatsql-operator/internal/consts"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -21,57 +21,88 @@ import (
  * @file: finalizer.go
  * @description: resource finalizer
  */
-type GreatSqlFinalizer struct {
-	Cli      client.Client
-	GreatSql *singlev1.SingleInstance
+
+// Stage orders the cleanup of an instance, the stages run one after the other
+type Stage int
+
+const (
+	// StageProxy removes the proxies first, so that clients stop sending traffic
+	StageProxy Stage = iota
+	// StageMembers removes the members and the resources they use
+	StageMembers
+	// StageStorage handles the data according to the deletion policy, once the members are gone
+	StageStorage
+)
+
+// CleanupFunc cleans up the resources of the instance at a stage,
+// it returns ErrCleanupInProgress to be called again later
+type CleanupFunc func(ctx context.Context, cli client.Client, obj client.Object) error
+
+// ErrCleanupInProgress is returned while a cleanup waits for something to finish,
+// the finalizer is kept and the deletion has to be requeued
+var ErrCleanupInProgress = errors.New("cleanup is in progress")
+
+type cleanup struct {
+	stage Stage
+	fn    CleanupFunc
 }
 
 var (
 	logger = ctrl.Log.WithName("greatsql-finalizer")
+
+	cleanupsMu sync.RWMutex
+	cleanups   = map[string][]cleanup{}
 )
 
-func (g *GreatSqlFinalizer) HandleFinalizer() error {
-	logger.WithValues("Request.Finalizer.Namespace", g.GreatSql.Namespace, "Request.Finalizer.Name", g.GreatSql.Name)
+// RegisterCleanup registers a cleanup of the kind at the stage,
+// the cleanups of a stage run in registration order
+func RegisterCleanup(kind string, stage Stage, fn CleanupFunc) {
+	cleanupsMu.Lock()
+	defer cleanupsMu.Unlock()
+	cleanups[kind] = append(cleanups[kind], cleanup{stage: stage, fn: fn})
+	sort.SliceStable(cleanups[kind], func(i, j int) bool {
+		return cleanups[kind][i].stage < cleanups[kind][j].stage
+	})
+}
 
-	if !g.GreatSql.ObjectMeta.DeletionTimestamp.IsZero() {
-		if controllerutil.ContainsFinalizer(g.GreatSql, consts.GreatSqlFinalizer) {
-			// Perform cleanup operations
-			if err := g.finalizePersistentVolumeClaims(); err != nil {
-				return err
-			}
+// GreatSqlFinalizer handles the finalizer of any kind of the operator
+type GreatSqlFinalizer struct {
+	Cli      client.Client
+	GreatSql client.Object
+	// Kind selects the registered cleanups
+	Kind string
+}
 
-			if err := g.finalizeDeployment(); err != nil {
-				return err
-			}
+// HandleFinalizer runs the registered cleanups of the deleted instance by stage and removes the finalizer
+func (g *GreatSqlFinalizer) HandleFinalizer() error {
+	log := logger.WithValues("Request.Finalizer.Namespace", g.GreatSql.GetNamespace(), "Request.Finalizer.Name", g.GreatSql.GetName(), "Kind", g.Kind)
 
-			if err := g.finalizeService(); err != nil {
-				return err
-			}
+	if g.GreatSql.GetDeletionTimestamp().IsZero() || !controllerutil.ContainsFinalizer(g.GreatSql, consts.GreatSqlFinalizer) {
+		return nil
+	}
 
-			if err := g.finalizeConfigMap(); err != nil {
-				return err
-			}
+	cleanupsMu.RLock()
+	steps := append([]cleanup{}, cleanups[g.Kind]...)
+	cleanupsMu.RUnlock()
 
-			// Remove finalizer
-			controllerutil.RemoveFinalizer(g.GreatSql, consts.GreatSqlFinalizer)
-			if err := g.Cli.Update(context.Background(), g.GreatSql); err != nil {
-				logger.Error(err, "Could not remove finalizer from GreatSql")
-				return err
+	for _, step := range steps {
+		if err := step.fn(context.Background(), g.Cli, g.GreatSql); err != nil {
+			if !errors.Is(err, ErrCleanupInProgress) {
+				log.Error(err, "Could not clean up", "Stage", step.stage)
 			}
+			return err
 		}
 	}
 
-	return nil
+	return g.RemoveFinalizer()
 }
 
 // AddFinalizer adds the finalizer to the GreatSql
 func (g *GreatSqlFinalizer) AddFinalizer() error {
-	logger.WithValues("Request.Finalizer.Namespace", g.GreatSql.Namespace, "Request.Finalizer.Name", g.GreatSql.Name)
-
 	if !controllerutil.ContainsFinalizer(g.GreatSql, consts.GreatSqlFinalizer) {
 		controllerutil.AddFinalizer(g.GreatSql, consts.GreatSqlFinalizer)
 		if err := g.Cli.Update(context.Background(), g.GreatSql); err != nil {
-			logger.Error(err, "Could not add finalizer to GreatSql")
+			logger.Error(err, "Could not add finalizer to GreatSql", "Name", g.GreatSql.GetName())
 			return err
 		}
 	}
@@ -81,12 +112,10 @@ func (g *GreatSqlFinalizer) AddFinalizer() error {
 
 // RemoveFinalizer removes the finalizer from the GreatSql
 func (g *GreatSqlFinalizer) RemoveFinalizer() error {
-	logger.WithValues("Request.Finalizer.Namespace", g.GreatSql.Namespace, "Request.Finalizer.Name", g.GreatSql.Name)
-
 	if controllerutil.ContainsFinalizer(g.GreatSql, consts.GreatSqlFinalizer) {
 		controllerutil.RemoveFinalizer(g.GreatSql, consts.GreatSqlFinalizer)
 		if err := g.Cli.Update(context.Background(), g.GreatSql); err != nil {
-			logger.Error(err, "Could not remove finalizer from GreatSql")
+			logger.Error(err, "Could not remove finalizer from GreatSql", "Name", g.GreatSql.GetName())
 			return err
 		}
 	}
@@ -94,74 +123,66 @@ func (g *GreatSqlFinalizer) RemoveFinalizer() error {
 	return nil
 }
 
-// finalizePersistentVolumeClaims removes the PVCs
-func (g *GreatSqlFinalizer) finalizePersistentVolumeClaims() error {
-	logger.WithValues("Request.Finalizer.Namespace", g.GreatSql.Namespace, "Request.Finalizer.Name", g.GreatSql.Name)
-
-	for i := 0; i < int(g.GreatSql.Spec.GetSize()); i++ {
-		pvcName := fmt.Sprintf("%s-db-%d", g.GreatSql.Name, i)
-		err := g.Cli.Delete(context.TODO(), &corev1.PersistentVolumeClaim{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      pvcName,
-				Namespace: g.GreatSql.Namespace,
-			},
-		})
-		if err != nil && !errors.IsNotFound(err) {
-			logger.Error(err, "Could not delete PersistentVolumeClaim "+pvcName)
+// DeleteObjects deletes the objects, the objects already gone are ignored
+func DeleteObjects(ctx context.Context, cli client.Client, objs ...client.Object) error {
+	for _, obj := range objs {
+		if err := cli.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Could not delete "+obj.GetName(), "Namespace", obj.GetNamespace())
 			return err
 		}
 	}
-
 	return nil
 }
 
-func (g *GreatSqlFinalizer) finalizeDeployment() error {
-	logger.WithValues("Request.Finalizer.Namespace", g.GreatSql.Namespace, "Request.Finalizer.Name", g.GreatSql.Name)
-
-	err := g.Cli.Delete(context.TODO(), &appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      g.GreatSql.Name,
-			Namespace: g.GreatSql.Namespace,
-		},
-	})
-	if err != nil && !errors.IsNotFound(err) {
-		logger.Error(err, "Could not delete Deployment "+g.GreatSql.Name)
+// WaitForPodsDeleted returns ErrCleanupInProgress while pods of the instance are left
+func WaitForPodsDeleted(ctx context.Context, cli client.Client, namespace, instance string) error {
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{
+		consts.AppKubernetesInstance: instance,
+	}); err != nil {
 		return err
 	}
-
+	if len(pods.Items) > 0 {
+		return ErrCleanupInProgress
+	}
 	return nil
 }
 
-func (g *GreatSqlFinalizer) finalizeService() error {
-	logger.WithValues("Request.Finalizer.Namespace", g.GreatSql.Namespace, "Request.Finalizer.Name", g.GreatSql.Name)
+// FinalizePersistentVolumeClaims handles the PVCs of the instance according to the deletion policy,
+// the PVCs are those labelled with the instance and the named ones
+func FinalizePersistentVolumeClaims(ctx context.Context, cli client.Client, namespace, instance string, policy greatsqlv1.DeletionPolicy, className *string, names ...string) error {
+	if policy != greatsqlv1.DeletionPolicyDelete && policy != greatsqlv1.DeletionPolicySnapshot {
+		logger.Info("Retain PersistentVolumeClaims", "Namespace", namespace, "Instance", instance)
+		return nil
+	}
 
-	err := g.Cli.Delete(context.TODO(), &corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      g.GreatSql.Name,
-			Namespace: g.GreatSql.Namespace,
-		},
-	})
-	if err != nil && !errors.IsNotFound(err) {
-		logger.Error(err, "Could not delete Service "+g.GreatSql.Name)
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := cli.List(ctx, pvcs, client.InNamespace(namespace), client.MatchingLabels{
+		consts.AppKubernetesInstance: instance,
+	}); err != nil {
 		return err
 	}
+	for _, pvc := range pvcs.Items {
+		names = append(names, pvc.Name)
+	}
 
-	return nil
-}
-
-func (g *GreatSqlFinalizer) finalizeConfigMap() error {
-	logger.WithValues("Request.Finalizer.Namespace", g.GreatSql.Namespace, "Request.Finalizer.Name", g.GreatSql.Name)
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
 
-	err := g.Cli.Delete(context.TODO(), &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      g.GreatSql.Name + "config",
-			Namespace: g.GreatSql.Namespace,
-		},
-	})
-	if err != nil && !errors.IsNotFound(err) {
-		logger.Error(err, "Could not delete ConfigMap "+g.GreatSql.Name)
-		return err
+		if policy == greatsqlv1.DeletionPolicySnapshot {
+			if err := TakeFinalSnapshot(ctx, cli, namespace, name, className); err != nil {
+				return err
+			}
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvc.Name, pvc.Namespace = name, namespace
+		if err := DeleteObjects(ctx, cli, pvc); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	greatsqlv1 "github.com/gagraler/greatsql-operator/api/v1"
+	"github.com/gagraler/greatsql-operator/internal/consts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 18:40:12
+ * @file: finalizer_test.go
+ * @description: resource finalizer test
+ */
+
+func TestHandleFinalizer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := greatsqlv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	now := metav1.Now()
+	instance := &greatsqlv1.SingleInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test",
+			Namespace:         "default",
+			Finalizers:        []string{consts.GreatSqlFinalizer},
+			DeletionTimestamp: &now,
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(instance).Build()
+
+	var order []Stage
+	storageDone := false
+	RegisterCleanup("Test", StageStorage, func(ctx context.Context, cli client.Client, obj client.Object) error {
+		order = append(order, StageStorage)
+		if !storageDone {
+			storageDone = true
+			return ErrCleanupInProgress
+		}
+		return nil
+	})
+	RegisterCleanup("Test", StageProxy, func(ctx context.Context, cli client.Client, obj client.Object) error {
+		order = append(order, StageProxy)
+		return nil
+	})
+	RegisterCleanup("Test", StageMembers, func(ctx context.Context, cli client.Client, obj client.Object) error {
+		order = append(order, StageMembers)
+		return nil
+	})
+
+	finalizer := &GreatSqlFinalizer{Cli: cli, GreatSql: instance, Kind: "Test"}
+
+	// the storage is still in progress, the finalizer is kept
+	if err := finalizer.HandleFinalizer(); err != ErrCleanupInProgress {
+		t.Fatalf("HandleFinalizer() = %v, want %v", err, ErrCleanupInProgress)
+	}
+	if !controllerutil.ContainsFinalizer(instance, consts.GreatSqlFinalizer) {
+		t.Fatal("finalizer removed while the cleanup is in progress")
+	}
+
+	if err := finalizer.HandleFinalizer(); err != nil {
+		t.Fatal(err)
+	}
+	if controllerutil.ContainsFinalizer(instance, consts.GreatSqlFinalizer) {
+		t.Fatal("finalizer kept after the cleanup")
+	}
+
+	want := []Stage{StageProxy, StageMembers, StageStorage, StageProxy, StageMembers, StageStorage}
+	if len(order) != len(want) {
+		t.Fatalf("cleanup order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("cleanup order = %v, want %v", order, want)
+		}
+	}
+}
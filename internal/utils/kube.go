@@ -20,6 +20,9 @@ import (
 // GetServiceAccessPoint returns the access point for a given service
 func GetServiceAccessPoint(svc corev1.Service) string {
 	var accessPoint string
+	if len(svc.Spec.Ports) == 0 {
+		return accessPoint
+	}
 	switch svc.Spec.Type {
 	case corev1.ServiceTypeClusterIP:
 		accessPoint = fmt.Sprintf("%s:%d", svc.Spec.ClusterIP, svc.Spec.Ports[0].Port)
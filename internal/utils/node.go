@@ -0,0 +1,59 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 23:06:51
+ * @file: node.go
+ * @description: node selector matching
+ */
+
+// nodeSelectorOperators maps the operators of a node selector requirement to the ones of a label selector
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// MatchNodeSelector returns true if the node matches one of the terms of the selector, as the scheduler does.
+// The requirements of a term are ANDed, an empty term or an invalid requirement matches no node
+func MatchNodeSelector(selector *corev1.NodeSelector, node *corev1.Node) bool {
+	if selector == nil {
+		return false
+	}
+	for _, term := range selector.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		if matchRequirements(term.MatchExpressions, node.Labels) &&
+			matchRequirements(term.MatchFields, map[string]string{"metadata.name": node.Name}) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRequirements returns true if the values match every requirement
+func matchRequirements(requirements []corev1.NodeSelectorRequirement, values map[string]string) bool {
+	selector := labels.NewSelector()
+	for _, requirement := range requirements {
+		operator, ok := nodeSelectorOperators[requirement.Operator]
+		if !ok {
+			return false
+		}
+		r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil {
+			return false
+		}
+		selector = selector.Add(*r)
+	}
+	return selector.Matches(labels.Set(values))
+}
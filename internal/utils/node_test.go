@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMatchNodeSelector(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "node-1",
+		Labels: map[string]string{"disktype": "nvme", "topology.kubernetes.io/zone": "a"},
+	}}
+	term := func(key string, operator corev1.NodeSelectorOperator, values ...string) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: key, Operator: operator, Values: values}}}
+	}
+
+	tests := []struct {
+		name     string
+		selector *corev1.NodeSelector
+		want     bool
+	}{
+		{"nil", nil, false},
+		{"empty term", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{}}}, false},
+		{"in", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term("disktype", corev1.NodeSelectorOpIn, "nvme", "ssd")}}, true},
+		{"not in", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term("disktype", corev1.NodeSelectorOpNotIn, "nvme")}}, false},
+		{"one term matches", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			term("disktype", corev1.NodeSelectorOpDoesNotExist), term("topology.kubernetes.io/zone", corev1.NodeSelectorOpExists),
+		}}, true},
+		{"field", &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchFields: []corev1.NodeSelectorRequirement{
+			{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}},
+		}}}}, true},
+	}
+	for _, tt := range tests {
+		if got := MatchNodeSelector(tt.selector, node); got != tt.want {
+			t.Errorf("%s: MatchNodeSelector() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gagraler/greatsql-operator/internal/pkg/kube"
+)
+
+/**
+ * @author: HuaiAn xu
+ * @date: 2026-10-19 18:02:37
+ * @file: snapshot.go
+ * @description: final snapshot of the data before the PersistentVolumeClaims are deleted
+ */
+
+// ErrFinalSnapshotInProgress is returned while the final snapshot is not ready to use,
+// the deletion of the instance is blocked until it is
+var ErrFinalSnapshotInProgress = fmt.Errorf("final snapshot is in progress: %w", ErrCleanupInProgress)
+
+// TakeFinalSnapshot creates the final VolumeSnapshot of the PersistentVolumeClaim and
+// returns nil once it is ready to use, a missing PersistentVolumeClaim has nothing to snapshot.
+// Only a snapshot of the current claim counts, the one left by an earlier instance with the same name is kept as is
+func TakeFinalSnapshot(ctx context.Context, cli client.Client, namespace, pvcName string, className *string) error {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: pvcName, Namespace: namespace}, pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	snapshot := kube.NewEmptyVolumeSnapshot()
+	key := client.ObjectKey{Name: kube.FinalSnapshotName(pvcName, pvc.UID), Namespace: namespace}
+	err := cli.Get(ctx, key, snapshot)
+	if apierrors.IsNotFound(err) {
+		if err := cli.Create(ctx, kube.NewVolumeSnapshot(key.Name, namespace, pvcName, pvc.UID, className)); err != nil {
+			logger.Error(err, "Could not create final snapshot", "Name", key.Name)
+			return err
+		}
+		logger.Info("Create final snapshot is successful", "Name", key.Name, "PersistentVolumeClaim", pvcName)
+		return ErrFinalSnapshotInProgress
+	}
+	if err != nil {
+		return err
+	}
+	if !kube.IsSnapshotOf(snapshot, pvcName, pvc.UID) {
+		// the claim must not be deleted without a snapshot of its data
+		return fmt.Errorf("final snapshot %s was not taken of %s", key.Name, pvcName)
+	}
+
+	ready, message := kube.VolumeSnapshotStatus(snapshot)
+	if message != "" {
+		return fmt.Errorf("final snapshot %s failed: %s", key.Name, message)
+	}
+	if !ready {
+		return ErrFinalSnapshotInProgress
+	}
+	return nil
+}